@@ -0,0 +1,10 @@
+// Package assets holds the game data compiled into the binary, so a release is a single file
+package assets
+
+import "embed"
+
+// FS has every file under assets/, paths don't include the assets/ prefix.
+// New folders (e.g. audio/) have to be added to the pattern.
+//
+//go:embed *.json *.png axolotl slime
+var FS embed.FS
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"axelot/pkg/console"
+	"axelot/pkg/nav"
+	"axelot/pkg/replay"
+	"axelot/pkg/world"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// registerCommands adds the console commands that reach across packages
+func registerCommands() {
+	// Cheats change the run, so what was recorded so far can't be played back anymore
+	console.OnCheat = func() {
+		if replay.IsRecording() {
+			replay.StopRecording()
+			console.Printf("replay recording stopped")
+		}
+	}
+
+	console.Register(console.Command{
+		Name:  "timescale",
+		Usage: "<scale>",
+		Help:  "game speed, 1 is normal",
+		Cheat: true,
+		Run: func(args []string) error {
+			if len(args) != 1 {
+				return fmt.Errorf("expected one number")
+			}
+			scale, err := strconv.ParseFloat(args[0], 32)
+			if err != nil || scale < 0 || scale > 10 {
+				return fmt.Errorf("%q is not a scale between 0 and 10", args[0])
+			}
+			timeScale = float32(scale)
+			tickBudget = 0
+			console.Printf("timescale = %g", scale)
+			return nil
+		},
+	})
+
+	console.Register(console.Command{
+		Name:  "loadmap",
+		Usage: "<file>",
+		Help:  "load another map, enemies keep their positions",
+		Cheat: true,
+		Run: func(args []string) error {
+			if len(args) != 1 {
+				return fmt.Errorf("expected a file")
+			}
+			if err := world.ReloadMap(args[0]); err != nil {
+				return err
+			}
+			nav.BuildGrid()
+			nav.ClearCache()
+			console.Printf("loaded %s", args[0])
+			return nil
+		},
+		Complete: func(arg int, prefix string) []string {
+			if arg != 0 {
+				return nil
+			}
+			files, _ := filepath.Glob(prefix + "*")
+			for i, file := range files {
+				if info, err := os.Stat(file); err == nil && info.IsDir() {
+					files[i] += string(filepath.Separator)
+				}
+			}
+			return files
+		},
+	})
+}
@@ -1,10 +1,30 @@
 package main
 
 import (
+	"axelot/pkg/asset"
+	"axelot/pkg/audio"
+	"axelot/pkg/console"
+	"axelot/pkg/debug"
+	"axelot/pkg/fx"
+	gameinput "axelot/pkg/input"
+	"axelot/pkg/nav"
+	"axelot/pkg/pickup"
 	"axelot/pkg/player"
+	"axelot/pkg/profile"
+	"axelot/pkg/projectile"
+	"axelot/pkg/replay"
+	"axelot/pkg/rng"
+	"axelot/pkg/savegame"
+	"axelot/pkg/scores"
 	"axelot/pkg/slime"
 	"axelot/pkg/ui"
+	"axelot/pkg/upgrade"
+	"axelot/pkg/waves"
 	"axelot/pkg/world"
+	"flag"
+	"fmt"
+	"os"
+	"time"
 
 	rl "github.com/gen2brain/raylib-go/raylib"
 )
@@ -20,16 +40,64 @@ var (
 	gameStarted  = false
 	survivalTime = 0
 	maxCombo     = 0
+
+	// Seed and unlock levels of the current run, shown in the run history and kept in saves
+	runSeed    int64
+	runLoadout map[string]int
+
+	// Finished run waiting for a name before it goes on the leaderboard
+	pendingScore scores.Entry
+
+	// Upgrades on the level up screen, replays record which one was picked
+	currentOffers []*upgrade.Def
+
+	// Game speed from the console, ticks are accumulated so 0.5 runs every other frame
+	timeScale  float32 = 1
+	tickBudget float32
+
+	// Parsed in init, everything is loaded before main runs
+	replayFile = flag.String("replay", "", "watch a replay file")
+	assetsDir  = flag.String("assets", "", "folder laid out like assets/ whose files replace the built-in ones")
 )
 
 func drawScene() {
 	world.DrawWorld()
+	fx.DrawParticles()
+	pickup.DrawPickups()
 	player.DrawChargeEffects() // Draw effects behind player
 	player.DrawPlayerTexture()
 	slime.DrawSlimeTexture()
+	projectile.DrawProjectiles()
+	if debug.Enabled {
+		drawDebugScene()
+	}
+}
+
+// drawDebugScene draws the collision grid, hitboxes and AI targets in world space
+func drawDebugScene() {
+	nav.DrawDebug()
+	world.DrawCollisionDebug()
+	pickup.DrawPickupDebug()
+	projectile.DrawProjectileDebug()
+	slime.DrawDebug()
+	player.DrawDebug()
+}
+
+// debugStats are the counters listed under the frame-time graph
+func debugStats() []string {
+	return []string{
+		fmt.Sprintf("slimes %d (%d alive)", slime.GetSlimeCount(), slime.GetAliveCount()),
+		fmt.Sprintf("projectiles %d", projectile.GetProjectileCount()),
+		fmt.Sprintf("pickups %d", pickup.GetPickupCount()),
+		fmt.Sprintf("particles %d", fx.GetParticleCount()),
+		fmt.Sprintf("hitboxes %d", len(player.GetActiveHitboxes())),
+		fmt.Sprintf("tick %d", survivalTime),
+	}
 }
 
 func init() {
+	flag.Parse()
+
 	rl.InitWindow(screenWidth, screenHeight, "axolotl - a game by joeel56")
 	rl.SetExitKey(0)
 	rl.SetTargetFPS(60)
@@ -37,13 +105,177 @@ func init() {
 	// Initialize UI system
 	ui.SetGameState(ui.MainMenu)
 
+	// A mods folder next to the game works without the flag
+	dir := *assetsDir
+	if dir == "" {
+		if info, err := os.Stat(asset.Path("mods")); err == nil && info.IsDir() {
+			dir = asset.Path("mods")
+		}
+	}
+	if err := asset.SetOverride(dir); err != nil {
+		fmt.Println("assets:", err, "- using built-in assets only")
+	}
+	if err := asset.LoadManifest("assets/manifest.json"); err != nil {
+		fmt.Println("assets:", err, "- using built-in manifest")
+	}
 	world.InitWorld()
 	world.LoadMap("assets/map.json")
+	nav.BuildGrid()
 	player.InitPlayer()
 	slime.InitSlime()
+	waves.InitWaves("assets/waves.json")
+	upgrade.InitUpgrades("assets/upgrades.json")
+	profile.InitProfile("assets/unlocks.json")
+	scores.InitScores()
+	ui.SetContinueAvailable(savegame.Exists())
+	registerProjectileTargets()
+	registerCommands()
+	replay.Build = replay.ComputeBuild("assets/map.json", "assets/enemies.json", "assets/waves.json",
+		"assets/weapons.json", "assets/upgrades.json", "assets/unlocks.json", "assets/axolotl/animations.json")
+	audio.InitAudio()
+	audio.PlayMusic(audio.MenuMusic)
+	asset.Report()
+}
+
+// registerProjectileTargets tells the projectile system how to hit the player and the enemies
+func registerProjectileTargets() {
+	projectile.RegisterTarget(projectile.Target{
+		Faction: projectile.PlayerFaction,
+		Hit: func(p *projectile.Projectile) bool {
+			// Shots splash on the player even while invulnerable
+			if rl.Vector2Distance(p.Pos, player.GetPlayerCenter()) > p.Def.Radius+6 {
+				return false
+			}
+			player.TakeHit(p.Hitbox.Damage, rl.Vector2Subtract(p.Pos, p.Vel))
+			return true
+		},
+		Nearest: func(from rl.Vector2, maxDist float32) (rl.Vector2, bool) {
+			center := player.GetPlayerCenter()
+			return center, rl.Vector2Distance(from, center) <= maxDist
+		},
+	})
+
+	projectile.RegisterTarget(projectile.Target{
+		Faction: projectile.EnemyFaction,
+		Hit: func(p *projectile.Projectile) bool {
+			if slime.ApplyHitbox(&p.Hitbox, onSlimeKilled) == 0 {
+				return false
+			}
+			player.RegisterHit()
+			return true
+		},
+		Nearest: func(from rl.Vector2, maxDist float32) (rl.Vector2, bool) {
+			id, ok := slime.GetClosestSlimeID(from)
+			if !ok {
+				return rl.Vector2{}, false
+			}
+			// Aim for the hitbox, it sits below the sprite center
+			box, ok := slime.GetSlimeHitBox(id)
+			center := rl.NewVector2(box.X+box.Width/2, box.Y+box.Height/2)
+			return center, ok && rl.Vector2Distance(from, center) <= maxDist
+		},
+	})
+}
+
+// startRun resets everything for a new run, replays call it with the recorded seed and loadout
+func startRun(seed int64, loadout map[string]int) {
+	ui.SetGameState(ui.Playing)
+	gameStarted = true
+	survivalTime = 0
+	maxCombo = 0
+	runSeed = seed
+	runLoadout = loadout
+
+	// Seed first, resetting the waves already rolls the first spawn queue
+	rng.Seed(seed)
+	gameinput.Reset()
+	nav.ResetNav()
+	player.ResetPlayer()
+	applyLoadout(loadout)
+	slime.ResetSlimes()
+	waves.ResetWaves()
+	fx.ClearParticles()
+	projectile.ClearProjectiles()
+	pickup.ClearPickups()
+}
+
+// applyLoadout carries the unlocks bought between runs into a fresh run
+func applyLoadout(loadout map[string]int) {
+	player.SetLockedWeapons(profile.LockedWeapons(loadout))
+	for _, bonus := range profile.Bonuses(loadout) {
+		player.ApplyBonus(bonus)
+	}
+	color, _ := profile.PlayerColor()
+	player.SetPlayerColor(color)
+}
+
+// endRun banks the run's shells, records it and shows the stats screen
+func endRun(state ui.GameState) {
+	// Watching a replay doesn't count as a run, it just stops at the end
+	if replay.IsPlaying() {
+		return
+	}
+
+	if r := replay.StopRecording(); r != nil && state == ui.GameOver {
+		if err := replay.Save(replay.Path("last_death.axr"), r); err != nil {
+			fmt.Println("replay:", err)
+		}
+	}
+
+	earned := profile.AwardRun(player.GetKillCount(), survivalTime, maxCombo, state == ui.Victory)
+	ui.SetGameOverStats(player.GetKillCount(), survivalTime, maxCombo, earned)
+	ui.SetGameState(state)
+
+	entry := scores.Entry{
+		Date:     time.Now(),
+		Seed:     runSeed,
+		Kills:    player.GetKillCount(),
+		Time:     survivalTime,
+		MaxCombo: maxCombo,
+		Level:    player.GetLevel(),
+		Victory:  state == ui.Victory,
+	}
+	scores.RecordRun(entry)
+	if scores.Qualifies(entry) {
+		pendingScore = entry
+		ui.StartNameEntry(scores.LastName())
+	}
+}
+
+func onSlimeKilled(xp int) {
+	player.IncrementKillCount()
+	player.AddXP(xp)
+}
+
+// offerUpgrades opens the level up screen, or skips the level up when everything is maxed
+func offerUpgrades() {
+	offers := upgrade.Roll(3)
+	if len(offers) == 0 {
+		player.SkipLevelUp()
+		return
+	}
+
+	// Replays take the recorded pick right away
+	if replay.IsPlaying() {
+		pick, ok := replay.NextEvent(replay.UpgradePick)
+		if !ok || pick >= len(offers) {
+			player.SkipLevelUp()
+			return
+		}
+		player.ApplyUpgrade(offers[pick])
+		return
+	}
+
+	currentOffers = offers
+	ui.ShowLevelUp(player.GetLevel()-player.PendingLevelUps()+1, offers)
 }
 
 func input() {
+	// The console takes the keyboard while it's open
+	if console.Update() {
+		return
+	}
+
 	currentState := ui.GetCurrentState()
 
 	// Handle menu input
@@ -53,6 +285,11 @@ func input() {
 		return
 	}
 
+	if replay.IsPlaying() {
+		handleReplayInput()
+		return
+	}
+
 	// In-game input
 	if rl.IsKeyPressed(rl.KeyP) {
 		ui.SetGameState(ui.Paused)
@@ -69,7 +306,10 @@ func input() {
 		rl.ToggleFullscreen()
 	}
 
-	player.PlayerInput()
+	// Hitboxes, ranges, the collision grid and performance
+	if rl.IsKeyPressed(rl.KeyF4) {
+		debug.Toggle()
+	}
 
 	if rl.IsKeyPressed(rl.KeyEscape) {
 		ui.SetGameState(ui.Paused)
@@ -84,12 +324,48 @@ func handleMenuAction(action ui.MenuOption) {
 
 	switch action {
 	case ui.StartGame:
+		loadout := profile.Loadout()
+		startRun(time.Now().UnixNano()&0xffffffff, loadout)
+		replay.StartRecording(runSeed, loadout)
+
+	case ui.ContinueGame:
+		continueRun()
+
+	case ui.SaveAndQuit:
+		saveAndQuit()
+
+	case ui.WatchReplay:
+		playReplayFile(replay.Path("last_death.axr"))
+
+	case ui.PickUpgrade:
+		if def := ui.GetSelectedUpgrade(); def != nil {
+			for i, offer := range currentOffers {
+				if offer == def {
+					replay.RecordEvent(replay.UpgradePick, i)
+				}
+			}
+			player.ApplyUpgrade(def)
+		}
 		ui.SetGameState(ui.Playing)
-		gameStarted = true
-		survivalTime = 0
-		maxCombo = 0
-		player.ResetPlayer()
-		slime.ResetSlimes()
+
+	case ui.UnlocksMenu:
+		ui.SetGameState(ui.Unlocks)
+
+	case ui.BuyUnlock:
+		if err := profile.Buy(ui.GetSelectedUnlock()); err != nil {
+			ui.SetUnlockMessage(err.Error())
+		} else {
+			ui.SetUnlockMessage("")
+		}
+
+	case ui.SubmitName:
+		scores.SubmitHighScore(pendingScore, ui.GetEnteredName())
+
+	case ui.HighScoresMenu:
+		ui.SetGameState(ui.HighScores)
+
+	case ui.HistoryMenu:
+		ui.SetGameState(ui.RunHistory)
 
 	case ui.SettingsMenu:
 		ui.SetGameState(ui.Settings)
@@ -117,16 +393,48 @@ func handleMenuAction(action ui.MenuOption) {
 }
 
 func update() {
+	debug.RecordFrame(rl.GetFrameTime())
+
 	// Check if window was closed, but don't override menu quit
 	if rl.WindowShouldClose() {
 		running = false
 	}
 
+	// Gameplay music keeps running while paused, menus and game over use the menu track
+	if gameStarted && ui.GetCurrentState() != ui.GameOver && ui.GetCurrentState() != ui.Victory {
+		audio.PlayMusic(audio.GameMusic)
+	} else {
+		audio.PlayMusic(audio.MenuMusic)
+	}
+	audio.UpdateAudio(player.Cam.Target)
+
 	// Only update game logic when playing
 	if ui.GetCurrentState() != ui.Playing {
 		return
 	}
 
+	if replay.IsPlaying() {
+		updatePlayback()
+		return
+	}
+
+	tickBudget += timeScale
+	for ; tickBudget >= 1 && ui.GetCurrentState() == ui.Playing; tickBudget-- {
+		// Typing into the console doesn't move the player
+		if console.IsOpen() {
+			gameinput.Set(0)
+		} else {
+			gameinput.Set(gameinput.Poll())
+		}
+		tick()
+	}
+}
+
+// tick advances the game by one frame with the current input, live and in replays
+func tick() {
+	replay.RecordTick(gameinput.Current(), stateChecksum)
+	player.PlayerInput()
+
 	// Track survival time and max combo
 	if gameStarted {
 		survivalTime++
@@ -137,31 +445,50 @@ func update() {
 		}
 	}
 
-	if player.IsPlayerDead() {
-		// Game over - show stats
-		ui.SetGameOverStats(player.GetKillCount(), survivalTime, maxCombo)
-		ui.SetGameState(ui.GameOver)
+	// The death animation plays out with the world moving on behind it, then the stats
+	dead := player.IsPlayerDead()
+	if dead && player.DeathFinished() {
+		endRun(ui.GameOver)
+		return
+	}
+
+	if slime.IsBossDefeated() && !dead {
+		endRun(ui.Victory)
+		return
+	}
+
+	// Level ups pause the fight until an upgrade is picked
+	if player.HasPendingLevelUp() && !dead {
+		offerUpgrades()
+		return
+	}
+
+	// The boss intro freezes the fight while the camera pans over
+	if slime.IsBossIntroPlaying() {
+		slime.UpdateBossIntro()
 		return
 	}
 
 	player.PlayerMoving()
+	fx.UpdateParticles()
+	nav.UpdateNav()
 
 	playerPos := rl.NewVector2(player.PlayerDest.X, player.PlayerDest.Y)
-	attackPlayerFunc := func() {
-		player.SetPlayerDamageState()
-		player.TakeDamage(0.7)
+	attackPlayerFunc := func(source rl.Vector2, damage float32) {
+		player.TakeHit(damage, source)
 	}
 
 	slime.SlimeMoving(playerPos, attackPlayerFunc)
-	slime.UpdateSlimeSpawning()
-
-	if slime.IsSlimeAlive() {
-		closestSlimeIndex := slime.GetClosestSlimeIndex(playerPos)
-		if closestSlimeIndex >= 0 {
-			slimePos := slime.GetSlimePositionByIndex(closestSlimeIndex)
-			player.TryAttack(slimePos, func(damage float32) {
-				slime.DamageSlime(closestSlimeIndex, damage, player.IncrementKillCount)
-			})
+	projectile.UpdateProjectiles()
+	if !player.IsPlayerDead() {
+		pickup.UpdatePickups(player.GetPlayerCenter(), player.ApplyPickup)
+	}
+	waves.UpdateWaves(survivalTime, player.GetKillCount())
+
+	player.TryAttack()
+	for _, hitbox := range player.GetActiveHitboxes() {
+		if slime.ApplyHitbox(hitbox, onSlimeKilled) > 0 {
+			player.RegisterHit()
 		}
 	}
 }
@@ -179,6 +506,9 @@ func render() {
 	shakeOffset := player.GetScreenShakeOffset()
 	cam.Target.X += shakeOffset.X
 	cam.Target.Y += shakeOffset.Y
+	if target, ok := slime.BossCameraTarget(cam.Target); ok {
+		cam.Target = target
+	}
 
 	rl.BeginMode2D(cam)
 	drawScene()
@@ -188,7 +518,12 @@ func render() {
 	if currentState == ui.Playing {
 		player.DrawHealthBar()
 		player.DrawKillCounter()
+		player.DrawPickupHUD()
 		player.DrawWeaponHUD()
+		player.DrawXPBar()
+		waves.DrawWaveHUD()
+		slime.DrawBossHUD()
+		replay.DrawPlaybackHUD()
 	}
 
 	// Render menu overlay
@@ -196,17 +531,26 @@ func render() {
 		ui.DrawMenu()
 	}
 
+	debug.DrawOverlay(debugStats())
+	console.Draw()
+
 	rl.EndDrawing()
 }
 
 func quit() {
+	audio.UnloadAudio()
 	player.UnloadPlayerTexture()
 	slime.UnloadSlimeTexture()
 	world.UnloadWorldTexture()
+	asset.UnloadAll()
 	rl.CloseWindow()
 }
 
 func main() {
+	if *replayFile != "" {
+		playReplayFile(*replayFile)
+	}
+
 	for running {
 		input()
 		update()
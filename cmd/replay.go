@@ -0,0 +1,109 @@
+package main
+
+import (
+	"axelot/pkg/audio"
+	gameinput "axelot/pkg/input"
+	"axelot/pkg/pickup"
+	"axelot/pkg/player"
+	"axelot/pkg/projectile"
+	"axelot/pkg/replay"
+	"axelot/pkg/rng"
+	"axelot/pkg/slime"
+	"axelot/pkg/ui"
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"math"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Left/Right jump this many ticks while watching
+const seekStep = 5 * 60
+
+// stateChecksum hashes what a desync would change first, positions, health, counts and the rng
+func stateChecksum() uint32 {
+	h := fnv.New32a()
+	var buf []byte
+	center := player.GetPlayerCenter()
+	buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(center.X))
+	buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(center.Y))
+	buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(player.GetCurrentHealth()))
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(player.GetKillCount()))
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(survivalTime))
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(slime.GetAliveCount()))
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(projectile.GetProjectileCount()))
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(pickup.GetPickupCount()))
+	for _, pos := range slime.GetSlimePositions() {
+		buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(pos.X))
+		buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(pos.Y))
+	}
+	h.Write(buf)
+	if state, err := rng.State(); err == nil {
+		h.Write(state)
+	}
+	return h.Sum32()
+}
+
+func playReplayFile(path string) {
+	r, err := replay.Load(path)
+	if err != nil {
+		fmt.Println("replay:", err)
+		return
+	}
+	replay.StartPlayback(r)
+	startRun(r.Seed, r.Loadout)
+}
+
+func stopPlayback() {
+	replay.StopPlayback()
+	audio.Muted = false
+	ui.SetGameState(ui.MainMenu)
+	gameStarted = false
+}
+
+func handleReplayInput() {
+	if rl.IsKeyPressed(rl.KeyEscape) {
+		stopPlayback()
+		return
+	}
+	if rl.IsKeyPressed(rl.KeySpace) {
+		replay.TogglePause()
+	}
+	for i, speed := range replay.Speeds {
+		if rl.IsKeyPressed(rl.KeyOne + int32(i)) {
+			replay.SetSpeed(speed)
+		}
+	}
+	if rl.IsKeyPressed(rl.KeyLeft) {
+		seekReplay(replay.Tick() - seekStep)
+	}
+	if rl.IsKeyPressed(rl.KeyRight) {
+		seekReplay(replay.Tick() + seekStep)
+	}
+	if rl.IsKeyPressed(rl.KeyHome) {
+		seekReplay(0)
+	}
+}
+
+// seekReplay restarts the run when going backwards, then fast-forwards over the next frames
+func seekReplay(tick int) {
+	if replay.Seek(tick) {
+		r := replay.Current()
+		startRun(r.Seed, r.Loadout)
+	}
+}
+
+// updatePlayback feeds recorded input into the game, several ticks per frame when sped up or seeking
+func updatePlayback() {
+	audio.Muted = replay.IsSeeking()
+	for steps := replay.StepsThisFrame(); steps > 0; steps-- {
+		state, ok := replay.NextInput(stateChecksum)
+		if !ok {
+			break
+		}
+		gameinput.Set(state)
+		tick()
+	}
+	audio.Muted = false
+}
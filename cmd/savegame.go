@@ -0,0 +1,100 @@
+package main
+
+import (
+	"axelot/pkg/fx"
+	gameinput "axelot/pkg/input"
+	"axelot/pkg/nav"
+	"axelot/pkg/pickup"
+	"axelot/pkg/player"
+	"axelot/pkg/profile"
+	"axelot/pkg/projectile"
+	"axelot/pkg/replay"
+	"axelot/pkg/rng"
+	"axelot/pkg/savegame"
+	"axelot/pkg/slime"
+	"axelot/pkg/ui"
+	"axelot/pkg/upgrade"
+	"axelot/pkg/waves"
+	"fmt"
+)
+
+// saveAndQuit writes the run in progress and goes back to the main menu
+func saveAndQuit() {
+	if replay.IsPlaying() {
+		return
+	}
+
+	state, err := rng.State()
+	if err == nil {
+		err = savegame.Save(&savegame.File{
+			Build:        replay.Build,
+			Seed:         runSeed,
+			RNG:          state,
+			Loadout:      runLoadout,
+			SurvivalTime: survivalTime,
+			MaxCombo:     maxCombo,
+			Player:       player.TakeSnapshot(),
+			Upgrades:     upgrade.Snapshot(),
+			Slimes:       slime.TakeSnapshot(),
+			Waves:        waves.TakeSnapshot(),
+			Pickups:      pickup.Snapshot(),
+		})
+	}
+	if err != nil {
+		fmt.Println("savegame:", err)
+		ui.SetMenuMessage("Could not save: " + err.Error())
+		return
+	}
+
+	// A saved run isn't over, so it isn't recorded or scored
+	replay.StopRecording()
+	gameStarted = false
+	ui.SetContinueAvailable(true)
+	ui.SetGameState(ui.MainMenu)
+}
+
+// continueRun loads the saved run, the save is used up once it's running again
+func continueRun() {
+	f, err := savegame.Load()
+	if err != nil {
+		fmt.Println("savegame:", err)
+		ui.SetContinueAvailable(savegame.Exists())
+		ui.SetMenuMessage("Could not continue: " + err.Error())
+		return
+	}
+
+	runSeed = f.Seed
+	runLoadout = f.Loadout
+	survivalTime = f.SurvivalTime
+	maxCombo = f.MaxCombo
+
+	gameinput.Reset()
+	nav.ResetNav()
+	fx.ClearParticles()
+	projectile.ClearProjectiles()
+	player.SetLockedWeapons(profile.LockedWeapons(f.Loadout))
+	player.RestoreSnapshot(f.Player)
+	color, _ := profile.PlayerColor()
+	player.SetPlayerColor(color)
+	upgrade.Restore(f.Upgrades)
+	waves.RestoreSnapshot(f.Waves)
+	pickup.Restore(f.Pickups)
+	err = slime.RestoreSnapshot(f.Slimes)
+
+	// The generator goes last, restoring everything else must not advance it
+	if err == nil {
+		err = rng.Restore(f.Seed, f.RNG)
+	}
+	if err != nil {
+		fmt.Println("savegame:", err)
+		ui.SetMenuMessage("Could not continue: " + err.Error())
+		return
+	}
+
+	// Replays start from a seed, a resumed run can't be recorded
+	replay.StopRecording()
+	savegame.Delete()
+	ui.SetContinueAvailable(false)
+	gameStarted = true
+	ui.SetGameState(ui.Playing)
+}
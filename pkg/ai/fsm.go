@@ -0,0 +1,241 @@
+package ai
+
+import (
+	"axelot/pkg/rng"
+	"fmt"
+)
+
+// Blackboard is the memory of one agent, conditions and actions read and write it
+type Blackboard struct {
+	values map[string]float32
+}
+
+func (b *Blackboard) Get(key string) float32 {
+	return b.values[key]
+}
+
+func (b *Blackboard) Set(key string, value float32) {
+	if b.values == nil {
+		b.values = map[string]float32{}
+	}
+	b.values[key] = value
+}
+
+// Tick counts a blackboard value down by one and reports whether it has run out
+func (b *Blackboard) Tick(key string) bool {
+	v := b.Get(key) - 1
+	b.Set(key, v)
+	return v <= 0
+}
+
+type Condition[A any] func(agent A, ctx *Context) bool
+type Action[A any] func(agent A, ctx *Context)
+
+// Context is handed to every condition and action
+type Context struct {
+	Board *Blackboard
+	Timer int // frames spent in the current state
+}
+
+type Transition[A any] struct {
+	To   string
+	When Condition[A]
+}
+
+type State[A any] struct {
+	Name string
+	Tag  int // free for the owner, e.g. to pick animation or steering per state
+
+	Enter  Action[A]
+	Update Action[A]
+	Exit   Action[A]
+
+	// Checked in order after Update, the first match wins
+	Transitions []Transition[A]
+}
+
+// Machine is a shared, read-only behavior definition, each agent keeps its own Runner
+type Machine[A any] struct {
+	Name    string
+	Initial string
+	states  map[string]*State[A]
+}
+
+func NewMachine[A any](name, initial string, states ...State[A]) (*Machine[A], error) {
+	m := &Machine[A]{Name: name, Initial: initial, states: map[string]*State[A]{}}
+	for i := range states {
+		s := states[i]
+		m.states[s.Name] = &s
+	}
+	if _, ok := m.states[initial]; !ok {
+		return nil, fmt.Errorf("%s: unknown initial state %q", name, initial)
+	}
+	for _, s := range m.states {
+		for _, t := range s.Transitions {
+			if _, ok := m.states[t.To]; !ok {
+				return nil, fmt.Errorf("%s: state %q goes to unknown state %q", name, s.Name, t.To)
+			}
+		}
+	}
+	return m, nil
+}
+
+// Runner is the per-agent state of a machine
+type Runner struct {
+	state string
+	last  string // where the last transition came from, for debugging
+	ctx   Context
+	board Blackboard
+}
+
+func (r *Runner) Current() string {
+	return r.state
+}
+
+func (r *Runner) Previous() string {
+	return r.last
+}
+
+func (r *Runner) Board() *Blackboard {
+	return &r.board
+}
+
+func (r *Runner) Timer() int {
+	return r.ctx.Timer
+}
+
+func (m *Machine[A]) context(r *Runner) *Context {
+	r.ctx.Board = &r.board
+	return &r.ctx
+}
+
+// Start puts the runner in the initial state
+func (m *Machine[A]) Start(agent A, r *Runner) {
+	r.state = ""
+	m.Set(agent, r, m.Initial)
+}
+
+// Set forces a state change from outside, e.g. when the agent gets stunned
+func (m *Machine[A]) Set(agent A, r *Runner, name string) {
+	next, ok := m.states[name]
+	if !ok {
+		return
+	}
+	ctx := m.context(r)
+	if current, ok := m.states[r.state]; ok && current.Exit != nil {
+		current.Exit(agent, ctx)
+	}
+	r.last = r.state
+	r.state = name
+	ctx.Timer = 0
+	if next.Enter != nil {
+		next.Enter(agent, ctx)
+	}
+}
+
+// Update runs the current state for one frame, then follows the first transition that matches
+func (m *Machine[A]) Update(agent A, r *Runner) {
+	state, ok := m.states[r.state]
+	if !ok {
+		m.Start(agent, r)
+		state = m.states[r.state]
+	}
+
+	ctx := m.context(r)
+	ctx.Timer++
+	if state.Update != nil {
+		state.Update(agent, ctx)
+	}
+
+	// Update may already have forced a new state
+	if r.state != state.Name {
+		return
+	}
+	for _, t := range state.Transitions {
+		if t.When == nil || t.When(agent, ctx) {
+			m.Set(agent, r, t.To)
+			return
+		}
+	}
+}
+
+func (m *Machine[A]) Tag(r *Runner) int {
+	if s, ok := m.states[r.state]; ok {
+		return s.Tag
+	}
+	return 0
+}
+
+// Condition helpers
+
+func Not[A any](c Condition[A]) Condition[A] {
+	return func(agent A, ctx *Context) bool { return !c(agent, ctx) }
+}
+
+func And[A any](conds ...Condition[A]) Condition[A] {
+	return func(agent A, ctx *Context) bool {
+		for _, c := range conds {
+			if !c(agent, ctx) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+func Or[A any](conds ...Condition[A]) Condition[A] {
+	return func(agent A, ctx *Context) bool {
+		for _, c := range conds {
+			if c(agent, ctx) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// After is true once the agent has been in the state for at least frames
+func After[A any](frames int) Condition[A] {
+	return func(agent A, ctx *Context) bool { return ctx.Timer >= frames }
+}
+
+// Chance rolls every time it is checked
+func Chance[A any](p float32) Condition[A] {
+	return func(agent A, ctx *Context) bool { return rng.Float32() < p }
+}
+
+// Below and Above compare a blackboard value
+func Below[A any](key string, limit float32) Condition[A] {
+	return func(agent A, ctx *Context) bool { return ctx.Board.Get(key) < limit }
+}
+
+func Above[A any](key string, limit float32) Condition[A] {
+	return func(agent A, ctx *Context) bool { return ctx.Board.Get(key) > limit }
+}
+
+// RunnerState is a copy of a runner, for save games
+type RunnerState struct {
+	State  string             `json:"state"`
+	Last   string             `json:"last,omitempty"`
+	Timer  int                `json:"timer"`
+	Values map[string]float32 `json:"values,omitempty"`
+}
+
+func (r *Runner) Save() RunnerState {
+	values := make(map[string]float32, len(r.board.values))
+	for key, value := range r.board.values {
+		values[key] = value
+	}
+	return RunnerState{State: r.state, Last: r.last, Timer: r.ctx.Timer, Values: values}
+}
+
+// Load puts the runner back without running Enter, the state continues where it was saved
+func (r *Runner) Load(s RunnerState) {
+	r.state = s.State
+	r.last = s.Last
+	r.ctx.Timer = s.Timer
+	r.board.values = map[string]float32{}
+	for key, value := range s.Values {
+		r.board.values[key] = value
+	}
+}
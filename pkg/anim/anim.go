@@ -0,0 +1,132 @@
+package anim
+
+import (
+	"axelot/pkg/asset"
+	"encoding/json"
+	"fmt"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// TickRate is how many game ticks make a second, clips advance per tick so replays stay exact
+const TickRate = 60
+
+// Event is a named moment of a clip, e.g. "hit" or "footstep"
+type Event struct {
+	Frame int    `json:"frame"`
+	Name  string `json:"name"`
+}
+
+// Sheet is a texture cut into equal frames
+type Sheet struct {
+	Texture     string  `json:"texture"` // asset manifest key or file path
+	FrameWidth  float32 `json:"frameWidth"`
+	FrameHeight float32 `json:"frameHeight"`
+
+	texture rl.Texture2D
+}
+
+// Clip is a run of frames on a sheet, reaching the right edge it goes on at the start of the next row
+type Clip struct {
+	Sheet  string  `json:"sheet"`
+	Row    int     `json:"row"`
+	Start  int     `json:"start,omitempty"` // first frame, counted from the start of Row
+	Frames int     `json:"frames"`
+	FPS    float32 `json:"fps"`
+	Loop   bool    `json:"loop"`
+	Events []Event `json:"events,omitempty"`
+
+	sheet *Sheet
+}
+
+// Library holds the sheets and named clips of one kind of entity
+type Library struct {
+	Sheets map[string]*Sheet `json:"sheets"`
+	Clips  map[string]*Clip  `json:"clips"`
+}
+
+func NewLibrary() *Library {
+	return &Library{Sheets: map[string]*Sheet{}, Clips: map[string]*Clip{}}
+}
+
+// LoadLibrary reads sheets and clips from a JSON file, textures are loaded separately
+func LoadLibrary(file string) (*Library, error) {
+	byteValue, err := asset.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	lib := NewLibrary()
+	if err := json.Unmarshal(byteValue, lib); err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	if err := lib.Link(); err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	return lib, nil
+}
+
+// Link checks every clip and connects it to its sheet, call it after adding clips by hand
+func (l *Library) Link() error {
+	for name, clip := range l.Clips {
+		sheet, ok := l.Sheets[clip.Sheet]
+		if !ok {
+			return fmt.Errorf("clip %s: unknown sheet %q", name, clip.Sheet)
+		}
+		if clip.Frames <= 0 || clip.FPS < 0 {
+			return fmt.Errorf("clip %s: frames must be positive and fps not negative", name)
+		}
+		for _, ev := range clip.Events {
+			if ev.Frame < 0 || ev.Frame >= clip.Frames {
+				return fmt.Errorf("clip %s: event %s on frame %d is outside the clip", name, ev.Name, ev.Frame)
+			}
+		}
+		clip.sheet = sheet
+	}
+	for name, sheet := range l.Sheets {
+		if sheet.FrameWidth <= 0 || sheet.FrameHeight <= 0 {
+			return fmt.Errorf("sheet %s: frame size must be positive", name)
+		}
+	}
+	return nil
+}
+
+// LoadTextures acquires every sheet's texture from the asset manager
+func (l *Library) LoadTextures() {
+	for _, sheet := range l.Sheets {
+		if sheet.texture.ID == 0 {
+			sheet.texture = asset.Acquire(sheet.Texture)
+		}
+	}
+}
+
+// SetTexture gives a sheet a texture that is loaded and owned elsewhere
+func (l *Library) SetTexture(sheet string, tex rl.Texture2D) {
+	if s, ok := l.Sheets[sheet]; ok {
+		s.texture = tex
+	}
+}
+
+// UnloadTextures releases what LoadTextures acquired
+func (l *Library) UnloadTextures() {
+	for _, sheet := range l.Sheets {
+		if sheet.texture.ID != 0 {
+			asset.Release(sheet.Texture)
+			sheet.texture = rl.Texture2D{}
+		}
+	}
+}
+
+// HasEvent reports whether a clip fires the named event at all
+func (l *Library) HasEvent(clip, event string) bool {
+	c, ok := l.Clips[clip]
+	if !ok {
+		return false
+	}
+	for _, ev := range c.Events {
+		if ev.Name == event {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,124 @@
+package anim
+
+import (
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Animator plays the clips of a library for one entity
+type Animator struct {
+	Lib *Library
+
+	name     string
+	clip     *Clip
+	frame    int
+	progress float32 // towards the next frame, 1 is a whole frame
+	entered  bool    // frame 0 events of a new clip still have to fire
+	done     bool
+	fired    []string
+}
+
+func NewAnimator(lib *Library) Animator {
+	return Animator{Lib: lib}
+}
+
+// Play switches to a clip, playing the clip that is already running does nothing
+func (a *Animator) Play(name string) {
+	if name == a.name {
+		return
+	}
+	a.Restart(name)
+}
+
+// Restart plays a clip from its first frame, even if it's already running
+func (a *Animator) Restart(name string) {
+	a.name = name
+	a.clip = a.Lib.Clips[name]
+	a.frame = 0
+	a.progress = 0
+	a.entered = true
+	a.done = false
+}
+
+// Update advances one tick and collects the events of every frame entered
+func (a *Animator) Update() {
+	a.fired = a.fired[:0]
+	if a.clip == nil {
+		return
+	}
+
+	if a.entered {
+		a.entered = false
+		a.fire(0)
+		return
+	}
+	if a.done {
+		return
+	}
+
+	a.progress += a.clip.FPS / TickRate
+	for a.progress >= 1 {
+		a.progress--
+		a.frame++
+		if a.frame >= a.clip.Frames {
+			if !a.clip.Loop {
+				a.frame = a.clip.Frames - 1
+				a.done = true
+				return
+			}
+			a.frame = 0
+		}
+		a.fire(a.frame)
+	}
+}
+
+func (a *Animator) fire(frame int) {
+	for _, ev := range a.clip.Events {
+		if ev.Frame == frame {
+			a.fired = append(a.fired, ev.Name)
+		}
+	}
+}
+
+// Fired reports whether the event happened during the last Update
+func (a *Animator) Fired(event string) bool {
+	for _, name := range a.fired {
+		if name == event {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *Animator) Current() string {
+	return a.name
+}
+
+func (a *Animator) Frame() int {
+	return a.frame
+}
+
+// Finished is true once a clip that doesn't loop shows its last frame
+func (a *Animator) Finished() bool {
+	return a.done
+}
+
+// Src is the part of the texture showing the current frame
+func (a *Animator) Src() (rl.Texture2D, rl.Rectangle) {
+	if a.clip == nil || a.clip.sheet == nil {
+		return rl.Texture2D{}, rl.Rectangle{}
+	}
+	s := a.clip.sheet
+	columns := max(int(float32(s.texture.Width)/s.FrameWidth), 1)
+	index := a.clip.Start + a.frame
+	col, row := index%columns, a.clip.Row+index/columns
+	return s.texture, rl.NewRectangle(float32(col)*s.FrameWidth, float32(row)*s.FrameHeight, s.FrameWidth, s.FrameHeight)
+}
+
+// Draw draws the current frame stretched over dest
+func (a *Animator) Draw(dest rl.Rectangle, tint rl.Color) {
+	tex, src := a.Src()
+	if tex.ID == 0 {
+		return
+	}
+	rl.DrawTexturePro(tex, src, dest, rl.NewVector2(0, 0), 0, tint)
+}
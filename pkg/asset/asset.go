@@ -0,0 +1,248 @@
+package asset
+
+import (
+	"axelot/assets"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Manifest names every texture the game loads, paths start with assets/ like the files in the repo
+type Manifest struct {
+	Textures map[string]string `json:"textures"`
+}
+
+// entry is one loaded texture, shared by everything that acquired it
+type entry struct {
+	path     string
+	texture  rl.Texture2D
+	refs     int
+	loadTime time.Duration
+	missing  bool
+	source   string // override, embedded or disk
+}
+
+var (
+	manifest = defaultManifest
+	loaded   = map[string]*entry{}
+
+	// Files under assets/ are compiled in, an override folder laid out the same way wins over them
+	embedded fs.FS = assets.FS
+	override string
+
+	// Shown instead of a texture that can't be loaded, so a missing file is obvious in game
+	placeholder     rl.Texture2D
+	placeholderSize int32 = 32
+)
+
+// The textures the game ships with, used when the manifest can't be read
+var defaultManifest = Manifest{Textures: map[string]string{
+	"world.tiles":          "assets/spritesheet.png",
+	"axolotl.sheet":        "assets/axolotl/spritesheet.png",
+	"axolotl.death":        "assets/axolotl/Axolotl_Pink_Death.png",
+	"axolotl.getting_up":   "assets/axolotl/Axolotl_Pink_Getting_Up.png",
+	"axolotl.resting":      "assets/axolotl/Axolotl_Pink_Resting.png",
+	"axolotl.resting_idle": "assets/axolotl/Axolotl_Pink_Resting_Idle.png",
+	"axolotl.floating":     "assets/axolotl/Axolotl_Pink_Floating.png",
+	"axolotl.preparing":    "assets/axolotl/Axolotl_Pink_Preparing_To_Swim.png",
+	"ui.player_health":     "assets/axolotl/Health_bar.png",
+	"ui.enemy_health":      "assets/axolotl/Health_Bars_001.png",
+	"enemy.jellyfish":      "assets/slime/jellyfish_slime.png",
+	"enemy.crab":           "assets/slime/spritesheet_slime.png",
+	"enemy.bog_idle":       "assets/slime/008_idle.png",
+	"enemy.bog_jump":       "assets/slime/008_jump.png",
+	"enemy.bog_dash":       "assets/slime/008_dash_v.png",
+	"enemy.bog_death":      "assets/slime/008_death.png",
+}}
+
+// LoadManifest reads the texture keys, the built-in ones stay when the file can't be read
+func LoadManifest(file string) error {
+	byteValue, err := ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	var m Manifest
+	if err := json.Unmarshal(byteValue, &m); err != nil {
+		return fmt.Errorf("%s: %w", file, err)
+	}
+	if len(m.Textures) == 0 {
+		return fmt.Errorf("%s: no textures", file)
+	}
+	manifest = m
+	return nil
+}
+
+// SetOverride makes files in dir replace the embedded ones, for mods and for editing assets without a rebuild.
+// dir mirrors assets/, e.g. dir/axolotl/spritesheet.png replaces assets/axolotl/spritesheet.png.
+func SetOverride(dir string) error {
+	if dir == "" {
+		override = ""
+		return nil
+	}
+	info, err := os.Stat(dir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+	override = dir
+	return nil
+}
+
+// Override is the folder set with SetOverride, empty when only the embedded files are used
+func Override() string {
+	return override
+}
+
+// Path finds a game file next to the executable, falling back to the working directory for go run
+func Path(name string) string {
+	if filepath.IsAbs(name) {
+		return name
+	}
+	if exe, err := os.Executable(); err == nil {
+		if dir, err := filepath.EvalSymlinks(filepath.Dir(exe)); err == nil {
+			path := filepath.Join(dir, name)
+			if _, err := os.Stat(path); err == nil {
+				return path
+			}
+		}
+	}
+	return name
+}
+
+// ReadFile reads a game file from the override folder, the embedded files or the disk, in that order
+func ReadFile(name string) ([]byte, error) {
+	data, _, err := readFile(name)
+	return data, err
+}
+
+func readFile(name string) ([]byte, string, error) {
+	if rel, ok := strings.CutPrefix(filepath.ToSlash(name), "assets/"); ok {
+		if override != "" {
+			if data, err := os.ReadFile(filepath.Join(override, filepath.FromSlash(rel))); err == nil {
+				return data, "override", nil
+			}
+		}
+		if data, err := fs.ReadFile(embedded, rel); err == nil {
+			return data, "embedded", nil
+		}
+	}
+	data, err := os.ReadFile(Path(name))
+	return data, "disk", err
+}
+
+// Acquire returns the texture for a manifest key or a file path, loading it on first use.
+// Every Acquire needs a Release.
+func Acquire(name string) rl.Texture2D {
+	if e, ok := loaded[name]; ok {
+		e.refs++
+		return e.texture
+	}
+
+	path, ok := manifest.Textures[name]
+	if !ok {
+		path = name
+	}
+	e := &entry{path: path, refs: 1}
+	start := time.Now()
+	if data, source, err := readFile(path); err == nil {
+		e.source = source
+		img := rl.LoadImageFromMemory(strings.ToLower(filepath.Ext(path)), data, int32(len(data)))
+		if rl.IsImageValid(img) {
+			e.texture = rl.LoadTextureFromImage(img)
+			rl.UnloadImage(img)
+		}
+	}
+	e.loadTime = time.Since(start)
+
+	if e.texture.ID == 0 {
+		fmt.Println("asset:", name, "can't be loaded from", path, "- using placeholder")
+		e.missing = true
+		e.texture = getPlaceholder()
+	}
+	loaded[name] = e
+	return e.texture
+}
+
+// Release gives back a texture from Acquire, the last release unloads it
+func Release(name string) {
+	e, ok := loaded[name]
+	if !ok {
+		return
+	}
+	e.refs--
+	if e.refs > 0 {
+		return
+	}
+	if !e.missing {
+		rl.UnloadTexture(e.texture)
+	}
+	delete(loaded, name)
+}
+
+// Magenta and black checks, the usual "texture missing" look
+func getPlaceholder() rl.Texture2D {
+	if placeholder.ID == 0 {
+		img := rl.GenImageChecked(int(placeholderSize), int(placeholderSize), 8, 8, rl.Magenta, rl.Black)
+		placeholder = rl.LoadTextureFromImage(img)
+		rl.UnloadImage(img)
+	}
+	return placeholder
+}
+
+// Report prints how many textures are loaded, how long they took and which are missing
+func Report() {
+	var total time.Duration
+	missing, overridden := 0, 0
+	for _, name := range names() {
+		e := loaded[name]
+		total += e.loadTime
+		if e.missing {
+			missing++
+		}
+		if e.source == "override" {
+			overridden++
+		}
+	}
+	fmt.Printf("asset: %d textures loaded in %.1fms, %d missing\n", len(loaded), float64(total.Microseconds())/1000, missing)
+	if override != "" {
+		fmt.Printf("asset: %d textures from %s\n", overridden, override)
+	}
+}
+
+// UnloadAll frees everything at shutdown, textures still held are reported as leaks
+func UnloadAll() {
+	for _, name := range names() {
+		e := loaded[name]
+		if e.refs > 0 {
+			fmt.Println("asset:", name, "still has", e.refs, "references at shutdown")
+		}
+		if !e.missing {
+			rl.UnloadTexture(e.texture)
+		}
+		delete(loaded, name)
+	}
+	if placeholder.ID != 0 {
+		rl.UnloadTexture(placeholder)
+		placeholder = rl.Texture2D{}
+	}
+}
+
+// names lists the loaded textures in a stable order
+func names() []string {
+	list := make([]string, 0, len(loaded))
+	for name := range loaded {
+		list = append(list, name)
+	}
+	sort.Strings(list)
+	return list
+}
@@ -0,0 +1,24 @@
+package asset
+
+import (
+	"axelot/pkg/console"
+)
+
+func init() {
+	console.Register(console.Command{
+		Name: "assets",
+		Help: "list loaded textures with their references and load times",
+		Run: func(args []string) error {
+			for _, name := range names() {
+				e := loaded[name]
+				status := e.source
+				if e.missing {
+					status = "MISSING"
+				}
+				console.Printf("%-22s refs %d  %5.1fms  %s (%s)", name, e.refs, float64(e.loadTime.Microseconds())/1000, e.path, status)
+			}
+			console.Printf("%d textures", len(loaded))
+			return nil
+		},
+	})
+}
@@ -0,0 +1,211 @@
+package audio
+
+import (
+	"axelot/pkg/ui"
+	"math"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Event is a one-shot sound effect triggered by gameplay
+type Event int
+
+const (
+	Attack Event = iota
+	ChargeRelease
+	Dash
+	SlimeHit
+	SlimeDeath
+	PlayerDamage
+	Pickup
+	eventCount
+)
+
+// Track is a looping background music track
+type Track int
+
+const (
+	NoTrack Track = iota - 1
+	MenuMusic
+	GameMusic
+	trackCount
+)
+
+// Bus is a mixer channel, the final volume is master * bus
+type Bus int
+
+const (
+	Master Bus = iota
+	MusicBus
+	SFXBus
+	busCount
+)
+
+var (
+	backend Backend = NullBackend{}
+
+	busVolume = [busCount]float32{1.0, 0.6, 0.8}
+
+	// Sound and music files are optional, missing ones fall back to synthesized sounds and tunes
+	soundFiles = [eventCount]string{
+		Attack:        "assets/audio/attack.wav",
+		ChargeRelease: "assets/audio/charge_release.wav",
+		Dash:          "assets/audio/dash.wav",
+		SlimeHit:      "assets/audio/slime_hit.wav",
+		SlimeDeath:    "assets/audio/slime_death.wav",
+		PlayerDamage:  "assets/audio/player_damage.wav",
+		Pickup:        "assets/audio/pickup.wav",
+	}
+	musicFiles = [trackCount]string{
+		MenuMusic: "assets/audio/menu.ogg",
+		GameMusic: "assets/audio/game.ogg",
+	}
+
+	// Positional sound
+	listener     rl.Vector2
+	hearingRange float32 = 320 // world units until a sound is silent
+	panRange     float32 = 160 // horizontal offset for full left/right pan
+
+	// Music crossfade
+	currentTrack  Track   = NoTrack
+	previousTrack Track   = NoTrack
+	fadeProgress  float32 = 1
+	fadeFrames    int     = 90 // 1.5 seconds at 60 FPS
+)
+
+func InitAudio() {
+	InitAudioWithBackend(NewRaylibBackend())
+}
+
+// InitAudioWithBackend is used to run without a sound device, e.g. InitAudioWithBackend(NullBackend{})
+func InitAudioWithBackend(b Backend) {
+	if !b.Open() {
+		b = NullBackend{}
+	}
+	backend = b
+
+	for ev := Event(0); ev < eventCount; ev++ {
+		backend.LoadSound(ev, soundFiles[ev], synthesize(sfxRecipes[ev]))
+	}
+	for t := Track(0); t < trackCount; t++ {
+		backend.LoadMusic(t, musicFiles[t], renderSong(musicRecipes[t]))
+	}
+
+	currentTrack = NoTrack
+	previousTrack = NoTrack
+	fadeProgress = 1
+}
+
+func UnloadAudio() {
+	backend.Close()
+	backend = NullBackend{}
+}
+
+// UpdateAudio restarts finished music loops, advances crossfades and moves the listener
+func UpdateAudio(listenerPos rl.Vector2) {
+	listener = listenerPos
+	busVolume[Master] = ui.GetMasterVolume()
+
+	if fadeProgress < 1 {
+		fadeProgress += 1.0 / float32(fadeFrames)
+		if fadeProgress >= 1 {
+			fadeProgress = 1
+			if previousTrack != NoTrack {
+				backend.StopMusic(previousTrack)
+				previousTrack = NoTrack
+			}
+		}
+	}
+
+	musicVolume := busVolume[Master] * busVolume[MusicBus]
+	if currentTrack != NoTrack {
+		backend.SetMusicVolume(currentTrack, musicVolume*fadeProgress)
+		backend.UpdateMusic(currentTrack)
+	}
+	if previousTrack != NoTrack {
+		backend.SetMusicVolume(previousTrack, musicVolume*(1-fadeProgress))
+		backend.UpdateMusic(previousTrack)
+	}
+}
+
+// PlayMusic crossfades to a track, calling it again with the same track does nothing
+func PlayMusic(track Track) {
+	if track == currentTrack {
+		return
+	}
+
+	// A fade still in progress is cut short
+	if previousTrack != NoTrack {
+		backend.StopMusic(previousTrack)
+	}
+
+	previousTrack = currentTrack
+	currentTrack = track
+	fadeProgress = 0
+
+	if currentTrack != NoTrack {
+		backend.SetMusicVolume(currentTrack, 0)
+		backend.PlayMusic(currentTrack)
+	}
+}
+
+func GetCurrentTrack() Track {
+	return currentTrack
+}
+
+// Muted silences sound effects, e.g. while fast-forwarding a replay
+var Muted bool
+
+// Play plays a sound without position, e.g. for the player's own actions
+func Play(ev Event) {
+	if Muted {
+		return
+	}
+	backend.PlaySound(ev, busVolume[Master]*busVolume[SFXBus], 0)
+}
+
+// PlayAt plays a sound in the world, volume and pan follow the distance to the listener
+func PlayAt(ev Event, pos rl.Vector2) {
+	volume, pan := Spatialize(pos)
+	if volume <= 0 || Muted {
+		return
+	}
+	backend.PlaySound(ev, busVolume[Master]*busVolume[SFXBus]*volume, pan)
+}
+
+// Spatialize returns volume (0..1) and pan (-1 left .. 1 right) for a world position
+func Spatialize(pos rl.Vector2) (float32, float32) {
+	dx := pos.X - listener.X
+	dy := pos.Y - listener.Y
+	dist := float32(math.Sqrt(float64(dx*dx + dy*dy)))
+
+	if dist >= hearingRange {
+		return 0, 0
+	}
+
+	// Quadratic falloff sounds more natural than linear
+	falloff := 1 - dist/hearingRange
+	volume := falloff * falloff
+
+	pan := dx / panRange
+	if pan > 1 {
+		pan = 1
+	} else if pan < -1 {
+		pan = -1
+	}
+
+	return volume, pan
+}
+
+func SetBusVolume(bus Bus, volume float32) {
+	if volume < 0 {
+		volume = 0
+	} else if volume > 1 {
+		volume = 1
+	}
+	busVolume[bus] = volume
+}
+
+func GetBusVolume(bus Bus) float32 {
+	return busVolume[bus]
+}
@@ -0,0 +1,132 @@
+package audio
+
+import (
+	"math"
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+func near(a, b float32) bool {
+	return math.Abs(float64(a-b)) < 1e-4
+}
+
+func TestPlayMusicCrossfade(t *testing.T) {
+	InitAudioWithBackend(NullBackend{})
+	defer UnloadAudio()
+
+	if GetCurrentTrack() != NoTrack {
+		t.Fatalf("current track after init = %d, want none", GetCurrentTrack())
+	}
+
+	PlayMusic(MenuMusic)
+	if GetCurrentTrack() != MenuMusic || previousTrack != NoTrack || fadeProgress != 0 {
+		t.Fatalf("after PlayMusic(menu): current %d previous %d fade %v", currentTrack, previousTrack, fadeProgress)
+	}
+
+	for i := 0; i < fadeFrames/2; i++ {
+		UpdateAudio(rl.Vector2{})
+	}
+	if want := float32(fadeFrames/2) / float32(fadeFrames); !near(fadeProgress, want) {
+		t.Fatalf("fade halfway = %v, want %v", fadeProgress, want)
+	}
+
+	// Same track again keeps the fade going
+	PlayMusic(MenuMusic)
+	if fadeProgress == 0 {
+		t.Fatal("playing the current track restarted the fade")
+	}
+
+	PlayMusic(GameMusic)
+	if currentTrack != GameMusic || previousTrack != MenuMusic || fadeProgress != 0 {
+		t.Fatalf("after switching: current %d previous %d fade %v", currentTrack, previousTrack, fadeProgress)
+	}
+
+	for i := 0; i < fadeFrames+1; i++ {
+		UpdateAudio(rl.Vector2{})
+	}
+	if fadeProgress != 1 || previousTrack != NoTrack {
+		t.Fatalf("after the fade: progress %v previous %d, want 1 and none", fadeProgress, previousTrack)
+	}
+}
+
+func TestBusVolumeClamps(t *testing.T) {
+	defer SetBusVolume(SFXBus, GetBusVolume(SFXBus))
+
+	tests := []struct {
+		set, want float32
+	}{
+		{0.5, 0.5},
+		{-1, 0},
+		{2, 1},
+		{0, 0},
+		{1, 1},
+	}
+	for _, tt := range tests {
+		SetBusVolume(SFXBus, tt.set)
+		if got := GetBusVolume(SFXBus); got != tt.want {
+			t.Errorf("SetBusVolume(%v) = %v, want %v", tt.set, got, tt.want)
+		}
+	}
+}
+
+func TestSpatialize(t *testing.T) {
+	listener = rl.NewVector2(100, 100)
+	defer func() { listener = rl.Vector2{} }()
+
+	tests := []struct {
+		name        string
+		pos         rl.Vector2
+		volume, pan float32
+	}{
+		{"on the listener", rl.NewVector2(100, 100), 1, 0},
+		{"half range to the right", rl.NewVector2(100+hearingRange/2, 100), 0.25, 1},
+		{"quarter pan to the left", rl.NewVector2(100-panRange/4, 100), (1 - panRange/4/hearingRange) * (1 - panRange/4/hearingRange), -0.25},
+		{"straight below", rl.NewVector2(100, 100+hearingRange/2), 0.25, 0},
+		{"at the edge", rl.NewVector2(100+hearingRange, 100), 0, 0},
+		{"out of range", rl.NewVector2(100-hearingRange*2, 100), 0, 0},
+	}
+	for _, tt := range tests {
+		volume, pan := Spatialize(tt.pos)
+		if !near(volume, tt.volume) || !near(pan, tt.pan) {
+			t.Errorf("%s: got volume %v pan %v, want %v and %v", tt.name, volume, pan, tt.volume, tt.pan)
+		}
+	}
+}
+
+func TestRaylibPan(t *testing.T) {
+	tests := []struct {
+		pan, want float32
+	}{
+		{0, 0.5},
+		{1, 0},  // right
+		{-1, 1}, // left
+		{0.5, 0.25},
+		{-0.5, 0.75},
+	}
+	for _, tt := range tests {
+		if got := raylibPan(tt.pan); !near(got, tt.want) {
+			t.Errorf("raylibPan(%v) = %v, want %v", tt.pan, got, tt.want)
+		}
+	}
+
+	// A sound to the right of the listener must end up on the right channel
+	listener = rl.Vector2{}
+	defer func() { listener = rl.Vector2{} }()
+	if _, pan := Spatialize(rl.NewVector2(panRange/2, 0)); raylibPan(pan) >= 0.5 {
+		t.Errorf("sound on the right got raylib pan %v, want below 0.5", raylibPan(pan))
+	}
+}
+
+func TestRenderSongLoops(t *testing.T) {
+	for track, s := range musicRecipes {
+		samples := renderSong(s)
+		step := int(60 / s.bpm / 2 * synthSampleRate)
+		if len(samples) != step*len(s.lead) {
+			t.Errorf("track %d: %d samples, want %d", track, len(samples), step*len(s.lead))
+		}
+		if last := samples[len(samples)-1]; last != 0 {
+			t.Errorf("track %d: loop ends on %d, want silence", track, last)
+		}
+	}
+}
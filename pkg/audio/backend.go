@@ -0,0 +1,186 @@
+package audio
+
+import (
+	"axelot/pkg/asset"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Backend owns the actual audio device, everything above it only deals with events and tracks
+type Backend interface {
+	Open() bool
+	Close()
+	LoadSound(ev Event, path string, fallback []int16)
+	PlaySound(ev Event, volume, pan float32)
+	LoadMusic(track Track, path string, fallback []int16)
+	PlayMusic(track Track)
+	StopMusic(track Track)
+	SetMusicVolume(track Track, volume float32)
+	UpdateMusic(track Track)
+}
+
+// NullBackend plays nothing, used in tests and when no audio device is available
+type NullBackend struct{}
+
+func (NullBackend) Open() bool                        { return true }
+func (NullBackend) Close()                            {}
+func (NullBackend) LoadSound(Event, string, []int16)  {}
+func (NullBackend) PlaySound(Event, float32, float32) {}
+func (NullBackend) LoadMusic(Track, string, []int16)  {}
+func (NullBackend) PlayMusic(Track)                   {}
+func (NullBackend) StopMusic(Track)                   {}
+func (NullBackend) SetMusicVolume(Track, float32)     {}
+func (NullBackend) UpdateMusic(Track)                 {}
+
+const soundVoices = 4 // same effect can overlap this many times
+
+type raylibBackend struct {
+	sounds    [eventCount][soundVoices]rl.Sound
+	hasSound  [eventCount]bool
+	nextVoice [eventCount]int
+
+	// Tracks are decoded up front and played as sounds, restarted when they end.
+	// A stream would keep reading the file from Go memory, which cgo doesn't allow.
+	music    [trackCount]rl.Sound
+	hasMusic [trackCount]bool
+	looping  [trackCount]bool
+}
+
+func NewRaylibBackend() Backend {
+	return &raylibBackend{}
+}
+
+func (b *raylibBackend) Open() bool {
+	rl.InitAudioDevice()
+	return rl.IsAudioDeviceReady()
+}
+
+func (b *raylibBackend) Close() {
+	for ev := range b.sounds {
+		if !b.hasSound[ev] {
+			continue
+		}
+		for v := 1; v < soundVoices; v++ {
+			rl.UnloadSoundAlias(b.sounds[ev][v])
+		}
+		rl.UnloadSound(b.sounds[ev][0])
+		b.hasSound[ev] = false
+	}
+
+	for t := range b.music {
+		if b.hasMusic[t] {
+			rl.UnloadSound(b.music[t])
+			b.hasMusic[t] = false
+			b.looping[t] = false
+		}
+	}
+
+	rl.CloseAudioDevice()
+}
+
+func (b *raylibBackend) LoadSound(ev Event, path string, fallback []int16) {
+	var sound rl.Sound
+
+	if data, err := asset.ReadFile(path); err == nil {
+		wave := rl.LoadWaveFromMemory(fileType(path), data, int32(len(data)))
+		sound = rl.LoadSoundFromWave(wave)
+		rl.UnloadWave(wave)
+	} else if len(fallback) > 0 {
+		sound = soundFromSamples(fallback)
+	}
+
+	if !rl.IsSoundValid(sound) {
+		fmt.Printf("audio: no sound for event %d (%s)\n", ev, path)
+		return
+	}
+
+	b.sounds[ev][0] = sound
+	for v := 1; v < soundVoices; v++ {
+		b.sounds[ev][v] = rl.LoadSoundAlias(sound)
+	}
+	b.hasSound[ev] = true
+}
+
+func (b *raylibBackend) PlaySound(ev Event, volume, pan float32) {
+	if !b.hasSound[ev] {
+		return
+	}
+
+	voice := b.sounds[ev][b.nextVoice[ev]]
+	b.nextVoice[ev] = (b.nextVoice[ev] + 1) % soundVoices
+
+	rl.SetSoundVolume(voice, volume)
+	rl.SetSoundPan(voice, raylibPan(pan))
+	rl.PlaySound(voice)
+}
+
+// raylibPan turns -1 (left) .. 1 (right) into raylib's pan, which goes from 1.0 on the left to 0.0 on the right
+func raylibPan(pan float32) float32 {
+	return 0.5 - pan*0.5
+}
+
+func (b *raylibBackend) LoadMusic(track Track, path string, fallback []int16) {
+	var music rl.Sound
+
+	if data, err := asset.ReadFile(path); err == nil {
+		wave := rl.LoadWaveFromMemory(fileType(path), data, int32(len(data)))
+		music = rl.LoadSoundFromWave(wave)
+		rl.UnloadWave(wave)
+	}
+	if !rl.IsSoundValid(music) && len(fallback) > 0 {
+		music = soundFromSamples(fallback)
+	}
+
+	if !rl.IsSoundValid(music) {
+		fmt.Printf("audio: no music for track %d (%s)\n", track, path)
+		return
+	}
+
+	b.music[track] = music
+	b.hasMusic[track] = true
+}
+
+func (b *raylibBackend) PlayMusic(track Track) {
+	if b.hasMusic[track] {
+		rl.PlaySound(b.music[track])
+		b.looping[track] = true
+	}
+}
+
+func (b *raylibBackend) StopMusic(track Track) {
+	if b.hasMusic[track] {
+		rl.StopSound(b.music[track])
+		b.looping[track] = false
+	}
+}
+
+func (b *raylibBackend) SetMusicVolume(track Track, volume float32) {
+	if b.hasMusic[track] {
+		rl.SetSoundVolume(b.music[track], volume)
+	}
+}
+
+func (b *raylibBackend) UpdateMusic(track Track) {
+	if b.looping[track] && !rl.IsSoundPlaying(b.music[track]) {
+		rl.PlaySound(b.music[track])
+	}
+}
+
+// soundFromSamples loads synthesized 16 bit mono samples, raylib copies them
+func soundFromSamples(samples []int16) rl.Sound {
+	data := make([]byte, len(samples)*2)
+	for i, s := range samples {
+		data[i*2] = byte(s)
+		data[i*2+1] = byte(s >> 8)
+	}
+	wave := rl.NewWave(uint32(len(samples)), synthSampleRate, 16, 1, data)
+	return rl.LoadSoundFromWave(wave)
+}
+
+// fileType is the extension raylib wants for loading from memory, e.g. ".wav"
+func fileType(path string) string {
+	return strings.ToLower(filepath.Ext(path))
+}
@@ -0,0 +1,170 @@
+package audio
+
+import "math"
+
+const synthSampleRate = 22050
+
+type waveShape int
+
+const (
+	sineWave waveShape = iota
+	squareWave
+	sawWave
+	noiseWave
+)
+
+// recipe describes a short retro sound effect used when no sound file is shipped
+type recipe struct {
+	shape    waveShape
+	startHz  float64
+	endHz    float64
+	duration float64 // seconds
+	noise    float64 // 0..1 noise mixed into the tone
+	volume   float64
+}
+
+var sfxRecipes = [eventCount]recipe{
+	Attack:        {shape: noiseWave, startHz: 900, endHz: 300, duration: 0.12, noise: 1, volume: 0.35},
+	ChargeRelease: {shape: sineWave, startHz: 600, endHz: 120, duration: 0.35, noise: 0.3, volume: 0.5},
+	Dash:          {shape: noiseWave, startHz: 300, endHz: 1200, duration: 0.2, noise: 1, volume: 0.3},
+	SlimeHit:      {shape: squareWave, startHz: 260, endHz: 180, duration: 0.08, noise: 0.1, volume: 0.3},
+	SlimeDeath:    {shape: squareWave, startHz: 420, endHz: 70, duration: 0.3, noise: 0.2, volume: 0.35},
+	PlayerDamage:  {shape: sawWave, startHz: 160, endHz: 90, duration: 0.18, noise: 0.15, volume: 0.45},
+	Pickup:        {shape: sineWave, startHz: 700, endHz: 1400, duration: 0.1, noise: 0, volume: 0.3},
+}
+
+// synthesize renders a recipe to 16 bit mono samples
+func synthesize(r recipe) []int16 {
+	count := int(r.duration * synthSampleRate)
+	samples := make([]int16, count)
+
+	phase := 0.0
+	seed := uint32(22222) // fixed seed, so the same recipe always sounds the same
+	noiseValue := 0.0
+
+	for i := 0; i < count; i++ {
+		t := float64(i) / float64(count)
+		freq := r.startHz + (r.endHz-r.startHz)*t
+		phase += freq / synthSampleRate
+		phase -= math.Floor(phase)
+
+		// Sample and hold noise at the tone frequency gives a crunchy retro noise
+		seed = seed*1664525 + 1013904223
+		if i == 0 || phase < freq/synthSampleRate {
+			noiseValue = float64(seed>>8)/float64(1<<24)*2 - 1
+		}
+
+		var tone float64
+		switch r.shape {
+		case sineWave:
+			tone = math.Sin(phase * 2 * math.Pi)
+		case squareWave:
+			if phase < 0.5 {
+				tone = 1
+			} else {
+				tone = -1
+			}
+		case sawWave:
+			tone = phase*2 - 1
+		case noiseWave:
+			tone = noiseValue
+		}
+
+		value := tone*(1-r.noise) + noiseValue*r.noise
+
+		// Short attack, then linear decay to avoid clicks
+		envelope := 1 - t
+		if t < 0.02 {
+			envelope = t / 0.02
+		}
+
+		samples[i] = int16(value * envelope * r.volume * math.MaxInt16)
+	}
+
+	return samples
+}
+
+// song is a short looping tune used when no music file is shipped, notes are MIDI numbers and -1 rests
+type song struct {
+	bpm       float64 // each note is an eighth
+	lead      []int
+	bass      []int // one bass note per four lead notes
+	leadShape waveShape
+	volume    float64
+}
+
+var musicRecipes = [trackCount]song{
+	// Slow and calm in A minor
+	MenuMusic: {
+		bpm:       84,
+		lead:      []int{69, -1, 72, 76, 74, -1, 72, -1, 71, -1, 67, 71, 72, -1, -1, -1, 69, -1, 72, 76, 79, -1, 77, 76, 74, -1, 71, 72, 69, -1, -1, -1},
+		bass:      []int{45, 45, 43, 43, 41, 41, 40, 40},
+		leadShape: sineWave,
+		volume:    0.3,
+	},
+	// Driving square lead for the fight
+	GameMusic: {
+		bpm:       132,
+		lead:      []int{64, 67, 69, 67, 64, -1, 62, 64, 67, 69, 72, 69, 67, -1, 64, -1, 64, 67, 69, 72, 74, 72, 69, 67, 69, -1, 67, 64, 62, -1, 64, -1},
+		bass:      []int{40, 40, 43, 43, 45, 45, 43, 38},
+		leadShape: squareWave,
+		volume:    0.22,
+	},
+}
+
+func midiToHz(note int) float64 {
+	return 440 * math.Pow(2, float64(note-69)/12)
+}
+
+// renderSong renders one loop of a song to 16 bit mono samples, the end joins the start without a click
+func renderSong(s song) []int16 {
+	step := int(60 / s.bpm / 2 * synthSampleRate)
+	samples := make([]int16, step*len(s.lead))
+
+	var leadPhase, bassPhase float64
+	for i := range samples {
+		n := i / step
+		t := float64(i%step) / float64(step)
+
+		value := 0.0
+		if note := s.lead[n]; note >= 0 {
+			leadPhase += midiToHz(note) / synthSampleRate
+			leadPhase -= math.Floor(leadPhase)
+
+			var tone float64
+			switch s.leadShape {
+			case squareWave:
+				if leadPhase < 0.5 {
+					tone = 1
+				} else {
+					tone = -1
+				}
+			case sawWave:
+				tone = leadPhase*2 - 1
+			default:
+				tone = math.Sin(leadPhase * 2 * math.Pi)
+			}
+			// Pluck, fading out over the note
+			envelope := 1 - t*0.7
+			if t < 0.02 {
+				envelope = t / 0.02
+			}
+			value += tone * envelope * 0.6
+		}
+
+		if len(s.bass) > 0 {
+			bassNote := s.bass[(n/4)%len(s.bass)]
+			bassPhase += midiToHz(bassNote) / synthSampleRate
+			bassPhase -= math.Floor(bassPhase)
+			// Triangle, soft under the lead
+			value += (1 - 4*math.Abs(bassPhase-0.5)) * 0.4
+		}
+
+		// Fade the last few milliseconds so the loop point doesn't pop
+		if tail := len(samples) - i; tail < 200 {
+			value *= float64(tail-1) / 200
+		}
+		samples[i] = int16(value * s.volume * math.MaxInt16)
+	}
+	return samples
+}
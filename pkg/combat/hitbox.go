@@ -0,0 +1,138 @@
+package combat
+
+import (
+	"math"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+type Shape int
+
+const (
+	ArcShape     Shape = iota // wedge in front of the attacker
+	CircleShape               // burst around the attacker
+	CapsuleShape              // swept circle from Start to End, e.g. a dash path
+)
+
+// Hitbox is a damaging area that lives for a few frames, every target is hit at most once
+type Hitbox struct {
+	Shape  Shape
+	Center rl.Vector2 // arc and circle center, capsule start
+	End    rl.Vector2 // capsule end
+	Radius float32
+	Facing rl.Vector2 // normalized, arc only
+	Arc    float32    // degrees, arc only
+	Damage float32
+	Frames int // frames left before the hitbox expires
+
+	hit      map[int]bool
+	hitCount int
+}
+
+func NewArc(center, facing rl.Vector2, radius, arc, damage float32, frames int) *Hitbox {
+	return &Hitbox{Shape: ArcShape, Center: center, Facing: rl.Vector2Normalize(facing), Radius: radius, Arc: arc, Damage: damage, Frames: frames, hit: map[int]bool{}}
+}
+
+func NewCircle(center rl.Vector2, radius, damage float32, frames int) *Hitbox {
+	return &Hitbox{Shape: CircleShape, Center: center, Radius: radius, Damage: damage, Frames: frames, hit: map[int]bool{}}
+}
+
+func NewCapsule(start, end rl.Vector2, radius, damage float32, frames int) *Hitbox {
+	return &Hitbox{Shape: CapsuleShape, Center: start, End: end, Radius: radius, Damage: damage, Frames: frames, hit: map[int]bool{}}
+}
+
+func (h *Hitbox) Active() bool {
+	return h.Frames > 0
+}
+
+// TryHit marks a target as hit and reports whether this is the first time
+func (h *Hitbox) TryHit(targetID int) bool {
+	if h.hit[targetID] {
+		return false
+	}
+	h.hit[targetID] = true
+	h.hitCount++
+	return true
+}
+
+func (h *Hitbox) HitCount() int {
+	return h.hitCount
+}
+
+// Overlaps checks the hitbox against a target's collision rectangle
+func (h *Hitbox) Overlaps(rect rl.Rectangle) bool {
+	switch h.Shape {
+	case CircleShape:
+		return rl.CheckCollisionCircleRec(h.Center, h.Radius, rect)
+
+	case CapsuleShape:
+		rectCenter := rl.NewVector2(rect.X+rect.Width/2, rect.Y+rect.Height/2)
+		closest := closestPointOnSegment(h.Center, h.End, rectCenter)
+		return rl.CheckCollisionCircleRec(closest, h.Radius, rect)
+
+	case ArcShape:
+		if !rl.CheckCollisionCircleRec(h.Center, h.Radius, rect) {
+			return false
+		}
+		if rl.CheckCollisionPointRec(h.Center, rect) {
+			return true
+		}
+
+		// Angle between facing and the direction to the nearest point of the target
+		nearest := rl.NewVector2(clamp(h.Center.X, rect.X, rect.X+rect.Width), clamp(h.Center.Y, rect.Y, rect.Y+rect.Height))
+		toTarget := rl.Vector2Normalize(rl.Vector2Subtract(nearest, h.Center))
+		dot := float64(rl.Vector2DotProduct(h.Facing, toTarget))
+		if dot > 1 {
+			dot = 1
+		} else if dot < -1 {
+			dot = -1
+		}
+		angle := math.Acos(dot) * 180 / math.Pi
+		return float32(angle) <= h.Arc/2
+	}
+
+	return false
+}
+
+func closestPointOnSegment(a, b, p rl.Vector2) rl.Vector2 {
+	ab := rl.Vector2Subtract(b, a)
+	lengthSqr := rl.Vector2DotProduct(ab, ab)
+	if lengthSqr == 0 {
+		return a
+	}
+	t := clamp(rl.Vector2DotProduct(rl.Vector2Subtract(p, a), ab)/lengthSqr, 0, 1)
+	return rl.Vector2Add(a, rl.Vector2Scale(ab, t))
+}
+
+func clamp(v, min, max float32) float32 {
+	if v < min {
+		return min
+	}
+	if v > max {
+		return max
+	}
+	return v
+}
+
+// Origin is where knockback pushes away from, dashes push sideways off their path
+func (h *Hitbox) Origin(target rl.Vector2) rl.Vector2 {
+	if h.Shape == CapsuleShape {
+		return closestPointOnSegment(h.Center, h.End, target)
+	}
+	return h.Center
+}
+
+// DrawDebug outlines the area the hitbox covers
+func (h *Hitbox) DrawDebug(color rl.Color) {
+	switch h.Shape {
+	case ArcShape:
+		angle := float32(math.Atan2(float64(h.Facing.Y), float64(h.Facing.X))) * rl.Rad2deg
+		rl.DrawCircleSectorLines(h.Center, h.Radius, angle-h.Arc/2, angle+h.Arc/2, 12, color)
+	case CircleShape:
+		rl.DrawCircleLinesV(h.Center, h.Radius, color)
+	case CapsuleShape:
+		rl.DrawCircleLinesV(h.Center, h.Radius, color)
+		rl.DrawCircleLinesV(h.End, h.Radius, color)
+		rl.DrawLineV(h.Center, h.End, color)
+	}
+}
@@ -0,0 +1,66 @@
+package combat
+
+import rl "github.com/gen2brain/raylib-go/raylib"
+
+// Shared hit response tuning, both player and enemies use it
+var (
+	KnockbackBase      float32 = 1.5 // push speed of a weak hit, pixels per frame
+	KnockbackPerDamage float32 = 1.0
+	KnockbackMax       float32 = 6
+	KnockbackFriction  float32 = 0.8 // speed kept each frame
+
+	HitStunBase      int     = 8
+	HitStunPerDamage float32 = 6
+	HitStunMax       int     = 45
+)
+
+// Impulse is a knockback velocity that fades out over a few frames
+type Impulse struct {
+	VX, VY float32
+}
+
+// Knockback pushes the target away from the source, harder hits push further
+func Knockback(source, target rl.Vector2, damage float32) Impulse {
+	dir := rl.Vector2Subtract(target, source)
+	if rl.Vector2Length(dir) == 0 {
+		dir = rl.NewVector2(0, 1)
+	}
+	dir = rl.Vector2Normalize(dir)
+
+	strength := KnockbackBase + KnockbackPerDamage*damage
+	if strength > KnockbackMax {
+		strength = KnockbackMax
+	}
+
+	return Impulse{VX: dir.X * strength, VY: dir.Y * strength}
+}
+
+func (i *Impulse) Active() bool {
+	return i.VX != 0 || i.VY != 0
+}
+
+// Step returns this frame's displacement and decays the impulse
+func (i *Impulse) Step() (float32, float32) {
+	dx, dy := i.VX, i.VY
+
+	i.VX *= KnockbackFriction
+	i.VY *= KnockbackFriction
+	if i.VX*i.VX+i.VY*i.VY < 0.01 {
+		i.Stop()
+	}
+
+	return dx, dy
+}
+
+// Stop is used when the target hits a wall
+func (i *Impulse) Stop() {
+	i.VX, i.VY = 0, 0
+}
+
+func HitStunFrames(damage float32) int {
+	frames := HitStunBase + int(HitStunPerDamage*damage)
+	if frames > HitStunMax {
+		frames = HitStunMax
+	}
+	return frames
+}
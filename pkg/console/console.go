@@ -0,0 +1,311 @@
+package console
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Command is something that can be typed into the console, packages register their own
+type Command struct {
+	Name     string
+	Usage    string // arguments, e.g. "<enemy> [count]"
+	Help     string
+	Cheat    bool                                  // changes the running game, see OnCheat
+	Run      func(args []string) error             // args don't include the name, check them before changing anything
+	Complete func(arg int, prefix string) []string // optional candidates for an argument
+}
+
+// CVar is a value that can be read and set while the game runs
+type CVar struct {
+	Name string
+	Help string
+	Get  func() string
+	Set  func(value string) error // leaves the value alone when it returns an error
+}
+
+var (
+	commands = map[string]*Command{}
+	cvars    = map[string]*CVar{}
+
+	// Called once a cheat or a cvar change went through, e.g. to drop the replay being recorded.
+	// Lines run between ticks, so nothing gets recorded in between. Bad arguments don't count.
+	OnCheat func()
+
+	open         bool
+	line         string
+	output       []string
+	maxOutput    = 200
+	history      []string
+	historyIndex int // len(history) while typing a new line
+	maxHistory   = 50
+	scroll       int // lines scrolled up from the newest
+
+	consoleHeight int32 = 280
+	fontSize      int32 = 14
+	lineHeight    int32 = 16
+)
+
+func init() {
+	Register(Command{Name: "help", Usage: "[name]", Help: "list commands, or describe one", Run: help, Complete: completeNames})
+	Register(Command{Name: "cvars", Help: "list cvars and their values", Run: listCVars})
+	Register(Command{Name: "clear", Help: "clear the console", Run: func(args []string) error {
+		output = output[:0]
+		scroll = 0
+		return nil
+	}})
+}
+
+// Register adds a command, registering the same name again replaces it
+func Register(cmd Command) {
+	commands[cmd.Name] = &cmd
+}
+
+func RegisterCVar(cv CVar) {
+	cvars[cv.Name] = &cv
+}
+
+// Printf writes a line to the console output
+func Printf(format string, args ...any) {
+	for _, text := range strings.Split(fmt.Sprintf(format, args...), "\n") {
+		output = append(output, text)
+	}
+	if len(output) > maxOutput {
+		output = output[len(output)-maxOutput:]
+	}
+	scroll = 0
+}
+
+func IsOpen() bool {
+	return open
+}
+
+// Execute runs one line the way it would run if typed in
+func Execute(text string) {
+	args := strings.Fields(text)
+	if len(args) == 0 {
+		return
+	}
+
+	if cmd, ok := commands[args[0]]; ok {
+		if err := cmd.Run(args[1:]); err != nil {
+			Printf("%s: %v", cmd.Name, err)
+			if cmd.Usage != "" {
+				Printf("usage: %s %s", cmd.Name, cmd.Usage)
+			}
+			return
+		}
+		if cmd.Cheat && OnCheat != nil {
+			OnCheat()
+		}
+		return
+	}
+
+	cv, ok := cvars[args[0]]
+	if !ok {
+		Printf("unknown command %q, try help", args[0])
+		return
+	}
+	if len(args) == 1 {
+		Printf("%s = %s  (%s)", cv.Name, cv.Get(), cv.Help)
+		return
+	}
+	if err := cv.Set(args[1]); err != nil {
+		Printf("%s: %v", cv.Name, err)
+		return
+	}
+	if OnCheat != nil {
+		OnCheat()
+	}
+	Printf("%s = %s", cv.Name, cv.Get())
+}
+
+func help(args []string) error {
+	if len(args) > 0 {
+		if cmd, ok := commands[args[0]]; ok {
+			Printf("%s %s - %s", cmd.Name, cmd.Usage, cmd.Help)
+			return nil
+		}
+		if cv, ok := cvars[args[0]]; ok {
+			Printf("%s = %s  (%s)", cv.Name, cv.Get(), cv.Help)
+			return nil
+		}
+		return fmt.Errorf("unknown name %q", args[0])
+	}
+	for _, name := range sortedKeys(commands) {
+		cmd := commands[name]
+		Printf("%s %s - %s", cmd.Name, cmd.Usage, cmd.Help)
+	}
+	Printf("type a cvar name to see it, or a name and a value to set it (cvars lists them)")
+	return nil
+}
+
+func listCVars(args []string) error {
+	for _, name := range sortedKeys(cvars) {
+		Printf("%s = %s  (%s)", name, cvars[name].Get(), cvars[name].Help)
+	}
+	return nil
+}
+
+func completeNames(arg int, prefix string) []string {
+	if arg != 0 {
+		return nil
+	}
+	return matching(append(sortedKeys(commands), sortedKeys(cvars)...), prefix)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// matching keeps the candidates starting with prefix
+func matching(candidates []string, prefix string) []string {
+	var out []string
+	for _, c := range candidates {
+		if strings.HasPrefix(c, prefix) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// Update handles the toggle key and typing, true while the console has the keyboard
+func Update() bool {
+	if rl.IsKeyPressed(rl.KeyGrave) {
+		open = !open
+		// Drop the typed backtick and anything queued before the toggle
+		for rl.GetCharPressed() > 0 {
+		}
+		return true
+	}
+	if !open {
+		return false
+	}
+
+	for char := rl.GetCharPressed(); char > 0; char = rl.GetCharPressed() {
+		if char >= 32 && char < 127 && char != '`' && char != '~' {
+			line += string(rune(char))
+		}
+	}
+
+	switch {
+	case rl.IsKeyPressed(rl.KeyBackspace) || rl.IsKeyPressedRepeat(rl.KeyBackspace):
+		if len(line) > 0 {
+			line = line[:len(line)-1]
+		}
+	case rl.IsKeyPressed(rl.KeyEnter):
+		submit()
+	case rl.IsKeyPressed(rl.KeyTab):
+		complete()
+	case rl.IsKeyPressed(rl.KeyUp):
+		if historyIndex > 0 {
+			historyIndex--
+			line = history[historyIndex]
+		}
+	case rl.IsKeyPressed(rl.KeyDown):
+		if historyIndex < len(history) {
+			historyIndex++
+			line = ""
+			if historyIndex < len(history) {
+				line = history[historyIndex]
+			}
+		}
+	case rl.IsKeyPressed(rl.KeyPageUp):
+		scroll = min(scroll+visibleLines()/2, max(0, len(output)-visibleLines()))
+	case rl.IsKeyPressed(rl.KeyPageDown):
+		scroll = max(scroll-visibleLines()/2, 0)
+	case rl.IsKeyPressed(rl.KeyEscape):
+		open = false
+	}
+	return true
+}
+
+func submit() {
+	text := strings.TrimSpace(line)
+	line = ""
+	if text == "" {
+		return
+	}
+	if len(history) == 0 || history[len(history)-1] != text {
+		history = append(history, text)
+		if len(history) > maxHistory {
+			history = history[1:]
+		}
+	}
+	historyIndex = len(history)
+
+	Printf("> %s", text)
+	Execute(text)
+}
+
+// complete finishes the word under the cursor, listing the candidates when there are several
+func complete() {
+	args := strings.Fields(line)
+	if len(args) == 0 || strings.HasSuffix(line, " ") {
+		args = append(args, "")
+	}
+	prefix := args[len(args)-1]
+
+	var candidates []string
+	if len(args) == 1 {
+		candidates = completeNames(0, prefix)
+	} else if cmd, ok := commands[args[0]]; ok && cmd.Complete != nil {
+		candidates = matching(cmd.Complete(len(args)-2, prefix), prefix)
+	}
+	if len(candidates) == 0 {
+		return
+	}
+
+	common := candidates[0]
+	for _, c := range candidates[1:] {
+		for !strings.HasPrefix(c, common) {
+			common = common[:len(common)-1]
+		}
+	}
+
+	line = line[:len(line)-len(prefix)] + common
+	if len(candidates) == 1 {
+		line += " "
+	} else {
+		Printf("%s", strings.Join(candidates, "  "))
+	}
+}
+
+func visibleLines() int {
+	return int((consoleHeight - lineHeight - 12) / lineHeight)
+}
+
+// Draw shows the console over the top of the screen when it's open
+func Draw() {
+	if !open {
+		return
+	}
+
+	screenWidth := int32(600)
+	rl.DrawRectangle(0, 0, screenWidth, consoleHeight, rl.NewColor(10, 20, 30, 220))
+	rl.DrawLine(0, consoleHeight, screenWidth, consoleHeight, rl.NewColor(90, 200, 180, 255))
+
+	// Newest output sits right above the input line
+	y := consoleHeight - 2*lineHeight - 8
+	for i := len(output) - 1 - scroll; i >= 0 && y >= 4; i-- {
+		rl.DrawText(output[i], 8, y, fontSize, rl.LightGray)
+		y -= lineHeight
+	}
+	if scroll > 0 {
+		more := fmt.Sprintf("-- %d more --", scroll)
+		rl.DrawText(more, screenWidth-8-rl.MeasureText(more, fontSize), 4, fontSize, rl.Gray)
+	}
+
+	prompt := "> " + line
+	if (int(rl.GetTime()*2))%2 == 0 {
+		prompt += "_"
+	}
+	rl.DrawText(prompt, 8, consoleHeight-lineHeight-4, fontSize, rl.White)
+}
@@ -0,0 +1,71 @@
+package console
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// FloatVar registers a float32 the console can read and set
+func FloatVar(name, help string, p *float32) {
+	RegisterCVar(CVar{
+		Name: name,
+		Help: help,
+		Get:  func() string { return strconv.FormatFloat(float64(*p), 'g', -1, 32) },
+		Set: func(value string) error {
+			v, err := strconv.ParseFloat(value, 32)
+			if err != nil {
+				return fmt.Errorf("%q is not a number", value)
+			}
+			*p = float32(v)
+			return nil
+		},
+	})
+}
+
+func IntVar(name, help string, p *int) {
+	RegisterCVar(CVar{
+		Name: name,
+		Help: help,
+		Get:  func() string { return strconv.Itoa(*p) },
+		Set: func(value string) error {
+			v, err := strconv.Atoi(value)
+			if err != nil {
+				return fmt.Errorf("%q is not a whole number", value)
+			}
+			*p = v
+			return nil
+		},
+	})
+}
+
+func BoolVar(name, help string, p *bool) {
+	RegisterCVar(CVar{
+		Name: name,
+		Help: help,
+		Get:  func() string { return strconv.FormatBool(*p) },
+		Set: func(value string) error {
+			v, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("%q is not true or false", value)
+			}
+			*p = v
+			return nil
+		},
+	})
+}
+
+// ParseFloats reads every argument as a number, for commands like teleport
+func ParseFloats(args []string, n int) ([]float32, error) {
+	if len(args) != n {
+		return nil, fmt.Errorf("expected %d numbers", n)
+	}
+	out := make([]float32, n)
+	for i, arg := range args {
+		v, err := strconv.ParseFloat(arg, 32)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a number", arg)
+		}
+		out[i] = float32(v)
+	}
+	return out, nil
+}
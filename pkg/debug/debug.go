@@ -0,0 +1,77 @@
+package debug
+
+import (
+	"axelot/pkg/console"
+	"fmt"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+var (
+	// Enabled shows the debug layer, toggled with F4 or the debug cvar
+	Enabled bool
+
+	// Frame times in milliseconds, a ring buffer for the graph
+	frameTimes = make([]float32, 120)
+	frameIndex int
+
+	graphHeight  int32   = 50
+	graphScaleMs float32 = 50 // frame time at the top of the graph
+	targetMs     float32 = 1000.0 / 60
+)
+
+func init() {
+	console.BoolVar("debug", "show hitboxes, AI state and performance", &Enabled)
+}
+
+func Toggle() {
+	Enabled = !Enabled
+}
+
+// RecordFrame stores how long the last frame took, call once per frame
+func RecordFrame(seconds float32) {
+	frameTimes[frameIndex] = seconds * 1000
+	frameIndex = (frameIndex + 1) % len(frameTimes)
+}
+
+// DrawOverlay draws FPS, the frame-time graph and one line per stat in the top right
+func DrawOverlay(stats []string) {
+	if !Enabled {
+		return
+	}
+
+	var total, worst float32
+	for _, ms := range frameTimes {
+		total += ms
+		worst = max(worst, ms)
+	}
+	average := total / float32(len(frameTimes))
+
+	width := int32(len(frameTimes)) + 8
+	x := int32(600) - width - 6
+	y := int32(6)
+	lines := append([]string{
+		fmt.Sprintf("FPS %d", rl.GetFPS()),
+		fmt.Sprintf("avg %.1fms  max %.1fms", average, worst),
+	}, stats...)
+	height := graphHeight + int32(len(lines))*14 + 14
+	rl.DrawRectangle(x, y, width, height, rl.NewColor(0, 0, 0, 170))
+
+	for i, line := range lines {
+		rl.DrawText(line, x+4, y+4+int32(i)*14, 10, rl.White)
+	}
+
+	// Oldest frame on the left, the line marks 60 FPS
+	graphY := y + height - 4
+	targetY := graphY - int32(targetMs/graphScaleMs*float32(graphHeight))
+	rl.DrawLine(x+4, targetY, x+width-4, targetY, rl.NewColor(0, 228, 48, 160))
+	for i := range frameTimes {
+		ms := frameTimes[(frameIndex+i)%len(frameTimes)]
+		bar := int32(min(ms/graphScaleMs, 1) * float32(graphHeight))
+		color := rl.SkyBlue
+		if ms > targetMs*1.5 {
+			color = rl.Red
+		}
+		rl.DrawLine(x+4+int32(i), graphY, x+4+int32(i), graphY-bar, color)
+	}
+}
@@ -0,0 +1,112 @@
+package fx
+
+import (
+	"math"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Style is how a particle is drawn
+type Style int
+
+const (
+	WaterStyle Style = iota // droplet, splash or bubble depending on speed and size
+	DropletStyle
+	SplashStyle
+	BubbleStyle
+	SquareStyle
+)
+
+func drawParticle(p *Particle) {
+	switch p.def.Style {
+	case WaterStyle:
+		DrawWaterParticle(p)
+	case DropletStyle:
+		DrawPixelDroplet(p)
+	case SplashStyle:
+		DrawPixelSplash(p)
+	case BubbleStyle:
+		DrawPixelBubble(p)
+	case SquareStyle:
+		size := int32(p.Size)
+		rl.DrawRectangle(int32(p.X)-size/2, int32(p.Y)-size/2, size, size, p.Color)
+	}
+}
+
+func DrawWaterParticle(p *Particle) {
+	// Pixel-art water particles based on size and velocity
+	speed := math.Sqrt(float64(p.VX*p.VX + p.VY*p.VY))
+
+	if speed > 3.0 {
+		// Fast moving = pixel droplet
+		DrawPixelDroplet(p)
+	} else if p.Size > 6 {
+		// Large = pixel splash
+		DrawPixelSplash(p)
+	} else {
+		// Small = pixel bubble
+		DrawPixelBubble(p)
+	}
+}
+
+func DrawPixelDroplet(p *Particle) {
+	// Pixel droplet - hand-drawn pixel pattern
+	x, y := int32(p.X), int32(p.Y)
+
+	// Main droplet body (3x4 pixel pattern)
+	rl.DrawRectangle(x-1, y-1, 3, 2, p.Color)
+	rl.DrawRectangle(x, y-2, 1, 1, p.Color)
+	rl.DrawRectangle(x-1, y+1, 3, 1, p.Color)
+
+	// Droplet tail (1x2 pixels behind)
+	angle := math.Atan2(float64(p.VY), float64(p.VX))
+	tailX := x - int32(math.Cos(angle)*4)
+	tailY := y - int32(math.Sin(angle)*4)
+
+	fadeColor := p.Color
+	fadeColor.A = fadeColor.A / 2
+	rl.DrawRectangle(tailX, tailY, 1, 2, fadeColor)
+}
+
+func DrawPixelSplash(p *Particle) {
+	// Pixel splash - scattered pixel pattern
+	x, y := int32(p.X), int32(p.Y)
+
+	// Main splash body
+	rl.DrawRectangle(x-2, y-1, 5, 3, p.Color)
+	rl.DrawRectangle(x-1, y-2, 3, 1, p.Color)
+	rl.DrawRectangle(x-1, y+2, 3, 1, p.Color)
+
+	// Scattered droplets around splash
+	fadeColor := p.Color
+	fadeColor.A = fadeColor.A / 2
+
+	rl.DrawRectangle(x-4, y, 1, 1, fadeColor)
+	rl.DrawRectangle(x+4, y-1, 1, 1, fadeColor)
+	rl.DrawRectangle(x, y-4, 1, 1, fadeColor)
+	rl.DrawRectangle(x-1, y+4, 1, 1, fadeColor)
+}
+
+func DrawPixelBubble(p *Particle) {
+	// Pixel bubble - simple but clean
+	x, y := int32(p.X), int32(p.Y)
+	size := int32(p.Size)
+
+	if size <= 3 {
+		// Small bubble (2x2)
+		rl.DrawRectangle(x, y, 2, 2, p.Color)
+		// Highlight pixel
+		highlight := rl.NewColor(255, 255, 255, p.Color.A/2)
+		rl.DrawRectangle(x, y, 1, 1, highlight)
+	} else {
+		// Medium bubble (3x3)
+		rl.DrawRectangle(x-1, y-1, 3, 3, p.Color)
+		rl.DrawRectangle(x, y-2, 1, 1, p.Color)
+		rl.DrawRectangle(x-2, y, 1, 1, p.Color)
+
+		// Highlight pixels
+		highlight := rl.NewColor(255, 255, 255, p.Color.A/2)
+		rl.DrawRectangle(x-1, y-1, 1, 1, highlight)
+		rl.DrawRectangle(x, y-1, 1, 1, highlight)
+	}
+}
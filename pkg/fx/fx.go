@@ -0,0 +1,276 @@
+package fx
+
+import (
+	"math"
+	"math/rand"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+const frameTime float32 = 1.0 / 60.0
+
+// Shape is where particles appear around the emitter position
+type Shape int
+
+const (
+	PointShape  Shape = iota
+	CircleShape       // random position and direction inside Radius
+	RingShape         // evenly spaced directions, good for splashes
+)
+
+// Range is a min/max pair, a random value in between is picked per particle
+type Range struct {
+	Min, Max float32
+}
+
+func (r Range) pick() float32 {
+	if r.Max <= r.Min {
+		return r.Min
+	}
+	return r.Min + rand.Float32()*(r.Max-r.Min)
+}
+
+// EmitterDef describes a particle effect as data
+type EmitterDef struct {
+	Rate  float32 // particles per frame for continuous emitters
+	Count int     // particles per burst
+
+	Shape  Shape
+	Radius Range // spawn distance from the center
+	Speed  Range // outwards speed in pixels per frame
+	Wobble float32
+	DriftX Range // extra velocity, e.g. negative DriftY makes bubbles rise
+	DriftY Range
+
+	Life Range // seconds
+	Size Range
+
+	Colors      []rl.Color // color over life, evenly spaced keys
+	ColorJitter rl.Color   // random amount added to each channel at spawn
+	Fade        bool       // alpha follows the remaining life
+
+	Style Style
+}
+
+type Particle struct {
+	X, Y    float32
+	VX, VY  float32
+	Life    float32
+	MaxLife float32
+	Size    float32
+	Color   rl.Color
+
+	def    *EmitterDef
+	jitter rl.Color
+}
+
+// Pool holds a fixed number of particles, when it is full new particles are dropped
+type Pool struct {
+	particles []Particle
+	count     int
+}
+
+func NewPool(capacity int) *Pool {
+	return &Pool{particles: make([]Particle, capacity)}
+}
+
+func (p *Pool) Spawn(particle Particle) bool {
+	if p.count >= len(p.particles) {
+		return false
+	}
+	p.particles[p.count] = particle
+	p.count++
+	return true
+}
+
+func (p *Pool) Update() {
+	for i := 0; i < p.count; {
+		particle := &p.particles[i]
+		particle.X += particle.VX
+		particle.Y += particle.VY
+		particle.Life -= frameTime
+
+		if particle.Life <= 0 {
+			// Swap with the last live particle, order doesn't matter
+			p.count--
+			p.particles[i] = p.particles[p.count]
+			continue
+		}
+
+		particle.Color = particle.colorAt(1 - particle.Life/particle.MaxLife)
+		i++
+	}
+}
+
+func (p *Pool) Draw() {
+	for i := 0; i < p.count; i++ {
+		drawParticle(&p.particles[i])
+	}
+}
+
+func (p *Pool) Clear() {
+	p.count = 0
+}
+
+func (p *Pool) Count() int {
+	return p.count
+}
+
+func (p *Pool) Capacity() int {
+	return len(p.particles)
+}
+
+// Burst spawns def.Count particles at once
+func (p *Pool) Burst(def *EmitterDef, x, y float32) {
+	p.BurstWithVelocity(def, x, y, 0, 0)
+}
+
+// BurstWithVelocity adds a base velocity to every particle, e.g. a trail behind a moving player
+func (p *Pool) BurstWithVelocity(def *EmitterDef, x, y, vx, vy float32) {
+	for i := 0; i < def.Count; i++ {
+		p.spawnFrom(def, i, def.Count, x, y, vx, vy)
+	}
+}
+
+func (p *Pool) spawnFrom(def *EmitterDef, index, total int, x, y, vx, vy float32) {
+	var angle float64
+	if def.Shape == RingShape && total > 0 {
+		angle = float64(index) * 2.0 * math.Pi / float64(total)
+	} else {
+		angle = rand.Float64() * 2.0 * math.Pi
+	}
+
+	dirX := float32(math.Cos(angle))
+	dirY := float32(math.Sin(angle))
+
+	if def.Shape != PointShape {
+		radius := def.Radius.pick()
+		x += dirX * radius
+		y += dirY * radius
+	}
+
+	speed := def.Speed.pick()
+	if def.Wobble != 0 {
+		speed *= 1.0 + float32(math.Sin(angle*4))*def.Wobble
+	}
+
+	jitter := rl.NewColor(
+		uint8(rand.Intn(int(def.ColorJitter.R)+1)),
+		uint8(rand.Intn(int(def.ColorJitter.G)+1)),
+		uint8(rand.Intn(int(def.ColorJitter.B)+1)),
+		uint8(rand.Intn(int(def.ColorJitter.A)+1)),
+	)
+
+	life := def.Life.pick()
+	particle := Particle{
+		X:       x,
+		Y:       y,
+		VX:      vx + dirX*speed + def.DriftX.pick(),
+		VY:      vy + dirY*speed + def.DriftY.pick(),
+		Life:    life,
+		MaxLife: life,
+		Size:    def.Size.pick(),
+		def:     def,
+		jitter:  jitter,
+	}
+	particle.Color = particle.colorAt(0)
+
+	p.Spawn(particle)
+}
+
+// colorAt returns the particle color at t (0 = spawn, 1 = dead)
+func (particle *Particle) colorAt(t float32) rl.Color {
+	colors := particle.def.Colors
+	if len(colors) == 0 {
+		return rl.White
+	}
+
+	var c rl.Color
+	if len(colors) == 1 || t <= 0 {
+		c = colors[0]
+	} else if t >= 1 {
+		c = colors[len(colors)-1]
+	} else {
+		pos := t * float32(len(colors)-1)
+		i := int(pos)
+		c = lerpColor(colors[i], colors[i+1], pos-float32(i))
+	}
+
+	c = addColor(c, particle.jitter)
+	if particle.def.Fade {
+		c.A = uint8(float32(c.A) * (1 - t))
+	}
+	return c
+}
+
+func lerpColor(a, b rl.Color, t float32) rl.Color {
+	return rl.NewColor(
+		uint8(float32(a.R)+(float32(b.R)-float32(a.R))*t),
+		uint8(float32(a.G)+(float32(b.G)-float32(a.G))*t),
+		uint8(float32(a.B)+(float32(b.B)-float32(a.B))*t),
+		uint8(float32(a.A)+(float32(b.A)-float32(a.A))*t),
+	)
+}
+
+func addColor(a, b rl.Color) rl.Color {
+	add := func(x, y uint8) uint8 {
+		if int(x)+int(y) > 255 {
+			return 255
+		}
+		return x + y
+	}
+	return rl.NewColor(add(a.R, b.R), add(a.G, b.G), add(a.B, b.B), add(a.A, b.A))
+}
+
+// Emitter spawns particles continuously at def.Rate particles per frame
+type Emitter struct {
+	Def         *EmitterDef
+	accumulator float32
+}
+
+func NewEmitter(def *EmitterDef) Emitter {
+	return Emitter{Def: def}
+}
+
+func (e *Emitter) Update(pool *Pool, x, y, vx, vy float32) {
+	e.accumulator += e.Def.Rate
+	for e.accumulator >= 1 {
+		pool.spawnFrom(e.Def, 0, 1, x, y, vx, vy)
+		e.accumulator--
+	}
+}
+
+func (e *Emitter) Reset() {
+	e.accumulator = 0
+}
+
+// Shared pool used by everything drawn in the world
+var effects = NewPool(2048)
+
+func Burst(def *EmitterDef, x, y float32) {
+	effects.Burst(def, x, y)
+}
+
+func BurstWithVelocity(def *EmitterDef, x, y, vx, vy float32) {
+	effects.BurstWithVelocity(def, x, y, vx, vy)
+}
+
+func Emit(e *Emitter, x, y, vx, vy float32) {
+	e.Update(effects, x, y, vx, vy)
+}
+
+func UpdateParticles() {
+	effects.Update()
+}
+
+func DrawParticles() {
+	effects.Draw()
+}
+
+func ClearParticles() {
+	effects.Clear()
+}
+
+func GetParticleCount() int {
+	return effects.Count()
+}
@@ -0,0 +1,158 @@
+package fx
+
+import rl "github.com/gen2brain/raylib-go/raylib"
+
+// Water bubbles rising around the player while charging
+var ChargeBubbles = EmitterDef{
+	Rate:        0.125,
+	Shape:       CircleShape,
+	Radius:      Range{15, 35},
+	DriftX:      Range{-0.75, 0.75},
+	DriftY:      Range{-1.67, -0.33}, // bubbles rise up
+	Life:        Range{1.0, 1.0},
+	Size:        Range{3, 8},
+	Colors:      []rl.Color{rl.NewColor(100, 150, 255, 255)},
+	ColorJitter: rl.NewColor(0, 105, 0, 0),
+	Fade:        true,
+	Style:       WaterStyle,
+}
+
+// Water splash in all directions on charge release
+var WaterBurst = EmitterDef{
+	Count:       15,
+	Shape:       RingShape,
+	Speed:       Range{3, 8},
+	Life:        Range{1.25, 1.25},
+	Size:        Range{4, 10},
+	Colors:      []rl.Color{rl.NewColor(50, 200, 255, 255)},
+	ColorJitter: rl.NewColor(100, 55, 0, 0),
+	Fade:        true,
+	Style:       WaterStyle,
+}
+
+// Expanding water wave at dash start, one emitter per ring
+var DashWave = []EmitterDef{
+	{
+		Count:  16,
+		Shape:  RingShape,
+		Speed:  Range{3.6, 3.6},
+		Wobble: 0.3,
+		Life:   Range{1.83, 1.83},
+		Size:   Range{3, 3},
+		Colors: []rl.Color{rl.NewColor(80, 180, 255, 180)},
+		Fade:   true,
+		Style:  WaterStyle,
+	},
+	{
+		Count:  16,
+		Shape:  RingShape,
+		Speed:  Range{5.4, 5.4},
+		Wobble: 0.3,
+		Life:   Range{1.5, 1.5},
+		Size:   Range{6, 6},
+		Colors: []rl.Color{rl.NewColor(80, 200, 255, 140)},
+		Fade:   true,
+		Style:  WaterStyle,
+	},
+	{
+		Count:  16,
+		Shape:  RingShape,
+		Speed:  Range{7.2, 7.2},
+		Wobble: 0.3,
+		Life:   Range{1.17, 1.17},
+		Size:   Range{9, 9},
+		Colors: []rl.Color{rl.NewColor(80, 220, 255, 100)},
+		Fade:   true,
+		Style:  WaterStyle,
+	},
+}
+
+// Water trail behind a dashing player, spawned with the inverse dash velocity
+var DashTrail = EmitterDef{
+	Rate:   1,
+	Shape:  CircleShape,
+	Radius: Range{0, 8},
+	DriftX: Range{-0.5, 0.5},
+	DriftY: Range{-0.5, 0.5},
+	Life:   Range{0.67, 0.67},
+	Size:   Range{3, 7},
+	Colors: []rl.Color{rl.NewColor(120, 200, 255, 180)},
+	Fade:   true,
+	Style:  WaterStyle,
+}
+
+// Water impact splash when a dash ends
+var DashImpact = EmitterDef{
+	Count:  12,
+	Shape:  RingShape,
+	Speed:  Range{2, 6},
+	DriftY: Range{-1, -1}, // slight upward bias
+	Life:   Range{1.0, 1.0},
+	Size:   Range{5, 12},
+	Colors: []rl.Color{rl.NewColor(60, 220, 255, 255)},
+	Fade:   true,
+	Style:  WaterStyle,
+}
+
+// Pink jelly bits when a jellyfish dies
+var JellySplat = EmitterDef{
+	Count:  10,
+	Shape:  RingShape,
+	Radius: Range{2, 6},
+	Speed:  Range{0.6, 1.6},
+	Life:   Range{0.5, 0.8},
+	Size:   Range{2, 4},
+	Colors: []rl.Color{rl.NewColor(255, 170, 230, 255), rl.NewColor(170, 110, 220, 255)},
+	Fade:   true,
+	Style:  BubbleStyle,
+}
+
+// Bubbles left behind by a flying water bolt
+var BoltTrail = EmitterDef{
+	Rate:   0.5,
+	Shape:  CircleShape,
+	Radius: Range{0, 2},
+	DriftY: Range{-0.3, 0},
+	Life:   Range{0.25, 0.4},
+	Size:   Range{2, 3},
+	Colors: []rl.Color{rl.NewColor(150, 220, 255, 200)},
+	Fade:   true,
+	Style:  BubbleStyle,
+}
+
+// Small splash when a water bolt hits something
+var BoltSplash = EmitterDef{
+	Count:  6,
+	Shape:  RingShape,
+	Speed:  Range{0.8, 1.6},
+	Life:   Range{0.3, 0.5},
+	Size:   Range{3, 5},
+	Colors: []rl.Color{rl.NewColor(120, 210, 255, 255)},
+	Fade:   true,
+	Style:  WaterStyle,
+}
+
+// Sparkle ring when a pickup is collected, colors are set per pickup
+var PickupSparkle = EmitterDef{
+	Count:  8,
+	Shape:  RingShape,
+	Radius: Range{1, 3},
+	Speed:  Range{0.8, 1.4},
+	Life:   Range{0.3, 0.45},
+	Size:   Range{2, 2},
+	Colors: []rl.Color{rl.White},
+	Fade:   true,
+	Style:  SquareStyle,
+}
+
+// Tiny puff at the feet on the footstep frames of the walk cycle
+var Footstep = EmitterDef{
+	Count:  3,
+	Shape:  RingShape,
+	Speed:  Range{0.3, 0.7},
+	Life:   Range{0.2, 0.35},
+	Size:   Range{2, 3},
+	Colors: []rl.Color{rl.NewColor(150, 200, 230, 200)},
+	Fade:   true,
+	Style:  WaterStyle,
+}
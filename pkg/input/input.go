@@ -0,0 +1,87 @@
+package input
+
+import rl "github.com/gen2brain/raylib-go/raylib"
+
+// State is the gameplay input of one tick, one bit per action, so replays can store it compactly
+type State uint32
+
+const (
+	MoveUp State = 1 << iota
+	MoveDown
+	MoveLeft
+	MoveRight
+	Sprint
+	Attack
+	Charge
+	Dash
+	NextWeapon
+	Weapon1 // Weapon1..Weapon9 are consecutive, see WeaponSlot
+)
+
+const weaponSlots = 9
+
+var bindings = []struct {
+	action State
+	keys   []int32
+}{
+	{MoveUp, []int32{rl.KeyW, rl.KeyUp}},
+	{MoveDown, []int32{rl.KeyS, rl.KeyDown}},
+	{MoveLeft, []int32{rl.KeyA, rl.KeyLeft}},
+	{MoveRight, []int32{rl.KeyD, rl.KeyRight}},
+	{Sprint, []int32{rl.KeyLeftShift, rl.KeyRightShift}},
+	{Attack, []int32{rl.KeyQ}},
+	{Charge, []int32{rl.KeyE}},
+	{Dash, []int32{rl.KeyR}},
+	{NextWeapon, []int32{rl.KeyTab}},
+}
+
+var current, previous State
+
+func WeaponSlot(slot int) State {
+	return Weapon1 << slot
+}
+
+// Poll reads the keyboard into a State
+func Poll() State {
+	var s State
+	for _, b := range bindings {
+		for _, key := range b.keys {
+			if rl.IsKeyDown(key) {
+				s |= b.action
+			}
+		}
+	}
+	for slot := 0; slot < weaponSlots; slot++ {
+		if rl.IsKeyDown(rl.KeyOne + int32(slot)) {
+			s |= WeaponSlot(slot)
+		}
+	}
+	return s
+}
+
+// Set makes s the input of the current tick, called once per tick
+func Set(s State) {
+	previous = current
+	current = s
+}
+
+func Current() State {
+	return current
+}
+
+func Reset() {
+	current, previous = 0, 0
+}
+
+func Down(action State) bool {
+	return current&action != 0
+}
+
+// Pressed and Released compare with the previous tick
+func Pressed(action State) bool {
+	return current&action != 0 && previous&action == 0
+}
+
+func Released(action State) bool {
+	return current&action == 0 && previous&action != 0
+}
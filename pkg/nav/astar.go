@@ -0,0 +1,209 @@
+package nav
+
+import (
+	"container/heap"
+	"math"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+const (
+	straightCost float32 = 1
+	diagonalCost float32 = 1.41421356
+
+	// Searches give up after this many tiles so an unreachable target can't stall a frame
+	maxExpanded = 4000
+)
+
+var neighbours = [8][2]int{
+	{1, 0}, {-1, 0}, {0, 1}, {0, -1},
+	{1, 1}, {1, -1}, {-1, 1}, {-1, -1},
+}
+
+type node struct {
+	index int
+	f     float32
+}
+
+type openSet []node
+
+func (o openSet) Len() int            { return len(o) }
+func (o openSet) Less(i, j int) bool  { return o[i].f < o[j].f }
+func (o openSet) Swap(i, j int)       { o[i], o[j] = o[j], o[i] }
+func (o *openSet) Push(x interface{}) { *o = append(*o, x.(node)) }
+func (o *openSet) Pop() interface{} {
+	old := *o
+	n := old[len(old)-1]
+	*o = old[:len(old)-1]
+	return n
+}
+
+// Search buffers are reused, a cell is only valid when its stamp matches the current search
+var (
+	gScore   []float32
+	cameFrom []int32
+	closed   []bool
+	stamp    []uint32
+	searchID uint32
+	open     openSet
+)
+
+func prepareSearch(size int) {
+	if len(stamp) != size {
+		gScore = make([]float32, size)
+		cameFrom = make([]int32, size)
+		closed = make([]bool, size)
+		stamp = make([]uint32, size)
+		searchID = 0
+	}
+	searchID++
+	open = open[:0]
+}
+
+func (g *Grid) visit(index int) {
+	if stamp[index] != searchID {
+		stamp[index] = searchID
+		gScore[index] = float32(math.MaxFloat32)
+		cameFrom[index] = -1
+		closed[index] = false
+	}
+}
+
+// octile distance, exact for 8-way movement without obstacles
+func heuristic(ax, ay, bx, by int) float32 {
+	dx := float32(math.Abs(float64(ax - bx)))
+	dy := float32(math.Abs(float64(ay - by)))
+	if dx < dy {
+		dx, dy = dy, dx
+	}
+	return straightCost*(dx-dy) + diagonalCost*dy
+}
+
+// FindTilePath runs A* between two tiles and returns the tiles from start to goal
+func (g *Grid) FindTilePath(sx, sy, gx, gy int, mobility Mobility) ([][2]int, bool) {
+	if !g.Walkable(sx, sy, mobility) || !g.Walkable(gx, gy, mobility) {
+		return nil, false
+	}
+
+	prepareSearch(g.Width * g.Height)
+	start := sy*g.Width + sx
+	goal := gy*g.Width + gx
+
+	g.visit(start)
+	gScore[start] = 0
+	heap.Push(&open, node{index: start, f: heuristic(sx, sy, gx, gy)})
+
+	expanded := 0
+	for open.Len() > 0 {
+		current := heap.Pop(&open).(node)
+		if closed[current.index] {
+			continue // stale duplicate
+		}
+		if current.index == goal {
+			return g.reconstruct(goal), true
+		}
+
+		closed[current.index] = true
+		expanded++
+		if expanded > maxExpanded {
+			return nil, false
+		}
+
+		cx, cy := current.index%g.Width, current.index/g.Width
+		for i, n := range neighbours {
+			nx, ny := cx+n[0], cy+n[1]
+			if !g.Walkable(nx, ny, mobility) {
+				continue
+			}
+
+			cost := straightCost
+			if i >= 4 {
+				// No cutting corners past a blocked tile
+				if !g.Walkable(cx+n[0], cy, mobility) || !g.Walkable(cx, cy+n[1], mobility) {
+					continue
+				}
+				cost = diagonalCost
+			}
+
+			next := ny*g.Width + nx
+			g.visit(next)
+			if closed[next] {
+				continue
+			}
+
+			score := gScore[current.index] + cost
+			if score < gScore[next] {
+				gScore[next] = score
+				cameFrom[next] = int32(current.index)
+				heap.Push(&open, node{index: next, f: score + heuristic(nx, ny, gx, gy)})
+			}
+		}
+	}
+
+	return nil, false
+}
+
+func (g *Grid) reconstruct(goal int) [][2]int {
+	var tiles [][2]int
+	for i := goal; i >= 0; i = int(cameFrom[i]) {
+		tiles = append(tiles, [2]int{i % g.Width, i / g.Width})
+	}
+	for l, r := 0, len(tiles)-1; l < r; l, r = l+1, r-1 {
+		tiles[l], tiles[r] = tiles[r], tiles[l]
+	}
+	return tiles
+}
+
+// smooth drops waypoints that can be skipped with a straight, clear line
+func (g *Grid) smooth(tiles [][2]int, radius float32, mobility Mobility) []rl.Vector2 {
+	if len(tiles) == 0 {
+		return nil
+	}
+
+	points := []rl.Vector2{g.TileCenter(tiles[0][0], tiles[0][1])}
+	anchor := 0
+	for anchor < len(tiles)-1 {
+		// Furthest tile still visible from the anchor
+		next := anchor + 1
+		for j := len(tiles) - 1; j > anchor+1; j-- {
+			from := g.TileCenter(tiles[anchor][0], tiles[anchor][1])
+			to := g.TileCenter(tiles[j][0], tiles[j][1])
+			if g.LineClear(from, to, radius, mobility) {
+				next = j
+				break
+			}
+		}
+		points = append(points, g.TileCenter(tiles[next][0], tiles[next][1]))
+		anchor = next
+	}
+	return points
+}
+
+// FindPath returns smoothed waypoints from start to goal in world coordinates, the first point is the start tile
+func FindPath(start, goal rl.Vector2, radius float32, mobility Mobility) ([]rl.Vector2, bool) {
+	sx, sy := grid.TileAt(start)
+	gx, gy := grid.TileAt(goal)
+
+	// Knockback can push a body slightly into a blocked tile, start from the nearest free one
+	sx, sy, ok := grid.nearestWalkable(sx, sy, mobility)
+	if !ok {
+		return nil, false
+	}
+	gx, gy, ok = grid.nearestWalkable(gx, gy, mobility)
+	if !ok {
+		return nil, false
+	}
+
+	key := cacheKey{start: sy*grid.Width + sx, goal: gy*grid.Width + gx, mobility: mobility}
+	if points, found := cachedPath(key); found {
+		return points, points != nil
+	}
+
+	tiles, found := grid.FindTilePath(sx, sy, gx, gy, mobility)
+	var points []rl.Vector2
+	if found {
+		points = grid.smooth(tiles, radius, mobility)
+	}
+	storePath(key, points)
+	return points, found
+}
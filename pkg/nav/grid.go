@@ -0,0 +1,180 @@
+package nav
+
+import (
+	"axelot/pkg/world"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Terrain flags for one tile, a tile can be both water and land (shore)
+type Terrain uint8
+
+const (
+	WaterTerrain Terrain = 1 << iota
+	LandTerrain
+)
+
+// Mobility decides which tiles an enemy can move through
+type Mobility string
+
+const (
+	Swimmer    Mobility = "swim"       // water only, land blocks like for the player
+	Amphibious Mobility = "amphibious" // can cross islands
+)
+
+type Grid struct {
+	Width    int
+	Height   int
+	TileSize float32
+	cells    []Terrain
+}
+
+var grid Grid
+
+// BuildGrid reads the tile layers of the loaded map, call it again after loading another map
+func BuildGrid() {
+	tileSize := world.WorldMap.TileSize
+	if tileSize <= 0 {
+		tileSize = 16
+	}
+
+	grid = Grid{
+		Width:    world.WorldMap.MapWidth,
+		Height:   world.WorldMap.MapHeight,
+		TileSize: float32(tileSize),
+	}
+
+	// Tiles outside the declared size still count, the map file isn't always exact
+	for _, layer := range [][]world.Tile{world.WaterTiles, world.GroundTiles} {
+		for _, tile := range layer {
+			if tile.X+1 > grid.Width {
+				grid.Width = tile.X + 1
+			}
+			if tile.Y+1 > grid.Height {
+				grid.Height = tile.Y + 1
+			}
+		}
+	}
+
+	grid.cells = make([]Terrain, grid.Width*grid.Height)
+	mark := func(tiles []world.Tile, t Terrain) {
+		for _, tile := range tiles {
+			if tile.X >= 0 && tile.Y >= 0 {
+				grid.cells[tile.Y*grid.Width+tile.X] |= t
+			}
+		}
+	}
+	mark(world.WaterTiles, WaterTerrain)
+	mark(world.GroundTiles, LandTerrain)
+
+	ClearCache()
+}
+
+func GetGrid() *Grid {
+	return &grid
+}
+
+func (g *Grid) inside(x, y int) bool {
+	return x >= 0 && y >= 0 && x < g.Width && y < g.Height
+}
+
+func (g *Grid) TerrainAt(x, y int) Terrain {
+	if !g.inside(x, y) {
+		return 0
+	}
+	return g.cells[y*g.Width+x]
+}
+
+// Walkable reports if a tile can be entered, anything outside the map is blocked
+func (g *Grid) Walkable(x, y int, mobility Mobility) bool {
+	t := g.TerrainAt(x, y)
+	if mobility == Amphibious {
+		return t != 0
+	}
+	return t&WaterTerrain != 0 && t&LandTerrain == 0
+}
+
+func (g *Grid) TileAt(pos rl.Vector2) (int, int) {
+	if g.TileSize <= 0 {
+		return -1, -1
+	}
+	// Floor instead of truncating so positions left of the map don't land on tile 0
+	x := int(pos.X / g.TileSize)
+	y := int(pos.Y / g.TileSize)
+	if pos.X < 0 {
+		x--
+	}
+	if pos.Y < 0 {
+		y--
+	}
+	return x, y
+}
+
+func (g *Grid) TileCenter(x, y int) rl.Vector2 {
+	return rl.NewVector2((float32(x)+0.5)*g.TileSize, (float32(y)+0.5)*g.TileSize)
+}
+
+func (g *Grid) WalkableAt(pos rl.Vector2, mobility Mobility) bool {
+	x, y := g.TileAt(pos)
+	return g.Walkable(x, y, mobility)
+}
+
+// LineClear checks a straight move from a to b for a body of the given radius
+func (g *Grid) LineClear(a, b rl.Vector2, radius float32, mobility Mobility) bool {
+	dist := rl.Vector2Distance(a, b)
+	if dist == 0 {
+		return g.WalkableAt(a, mobility)
+	}
+
+	// Perpendicular offset so wide enemies don't clip corners
+	nx := -(b.Y - a.Y) / dist * radius
+	ny := (b.X - a.X) / dist * radius
+
+	step := g.TileSize / 4
+	steps := int(dist/step) + 1
+	for i := 0; i <= steps; i++ {
+		t := float32(i) / float32(steps)
+		x := a.X + (b.X-a.X)*t
+		y := a.Y + (b.Y-a.Y)*t
+		if !g.WalkableAt(rl.NewVector2(x, y), mobility) ||
+			!g.WalkableAt(rl.NewVector2(x+nx, y+ny), mobility) ||
+			!g.WalkableAt(rl.NewVector2(x-nx, y-ny), mobility) {
+			return false
+		}
+	}
+	return true
+}
+
+// nearestWalkable finds the closest walkable tile in a small square around x, y
+func (g *Grid) nearestWalkable(x, y int, mobility Mobility) (int, int, bool) {
+	if g.Walkable(x, y, mobility) {
+		return x, y, true
+	}
+	for r := 1; r <= 3; r++ {
+		for dy := -r; dy <= r; dy++ {
+			for dx := -r; dx <= r; dx++ {
+				if dx != -r && dx != r && dy != -r && dy != r {
+					continue // only the ring at distance r
+				}
+				if g.Walkable(x+dx, y+dy, mobility) {
+					return x + dx, y + dy, true
+				}
+			}
+		}
+	}
+	return x, y, false
+}
+
+// DrawDebug tints every tile by terrain, tiles swimmers can't enter are red
+func DrawDebug() {
+	size := grid.TileSize
+	for y := 0; y < grid.Height; y++ {
+		for x := 0; x < grid.Width; x++ {
+			color := rl.NewColor(60, 120, 255, 40)
+			if !grid.Walkable(x, y, Swimmer) {
+				color = rl.NewColor(255, 60, 60, 60)
+			}
+			rl.DrawRectangleV(rl.NewVector2(float32(x)*size, float32(y)*size), rl.NewVector2(size, size), color)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package nav
+
+import (
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+var (
+	// Re-plan when the target has moved this many tiles away from the planned goal
+	ReplanDistance float32 = 2
+	// Re-plan at least this often even if the target stands still, in frames
+	ReplanInterval int = 45
+	// Cached paths are shared by enemies starting on the same tile, for this many frames
+	CacheLifetime int = 30
+	maxCacheSize      = 512
+	waypointReach     = 4 // pixels, close enough to a waypoint to move on
+)
+
+type cacheKey struct {
+	start, goal int
+	mobility    Mobility
+}
+
+type cacheEntry struct {
+	points []rl.Vector2 // nil means no path was found
+	frame  int
+}
+
+var (
+	pathCache = map[cacheKey]cacheEntry{}
+	navFrame  int
+)
+
+// UpdateNav ages the path cache, call once per game frame
+func UpdateNav() {
+	navFrame++
+	if navFrame%CacheLifetime == 0 {
+		for key, entry := range pathCache {
+			if navFrame-entry.frame >= CacheLifetime {
+				delete(pathCache, key)
+			}
+		}
+	}
+}
+
+func ClearCache() {
+	pathCache = map[cacheKey]cacheEntry{}
+}
+
+// ResetNav starts the frame counter over with an empty cache, for a new run
+func ResetNav() {
+	ClearCache()
+	navFrame = 0
+}
+
+func cachedPath(key cacheKey) ([]rl.Vector2, bool) {
+	entry, ok := pathCache[key]
+	if !ok || navFrame-entry.frame >= CacheLifetime {
+		return nil, false
+	}
+	return entry.points, true
+}
+
+func storePath(key cacheKey, points []rl.Vector2) {
+	if len(pathCache) >= maxCacheSize {
+		ClearCache()
+	}
+	pathCache[key] = cacheEntry{points: points, frame: navFrame}
+}
+
+// Path follows a planned route and re-plans on its own when the target moves
+type Path struct {
+	points  []rl.Vector2
+	next    int
+	goal    rl.Vector2
+	planned int // navFrame of the last plan
+	valid   bool
+}
+
+// Direction returns the normalized direction to move from pos towards goal,
+// ok is false when the goal can't be reached
+func (p *Path) Direction(pos, goal rl.Vector2, radius float32, mobility Mobility) (rl.Vector2, bool) {
+	// Nothing in the way, skip the search entirely
+	if grid.LineClear(pos, goal, radius, mobility) {
+		p.valid = false
+		return direction(pos, goal), true
+	}
+
+	moved := rl.Vector2Distance(goal, p.goal) > ReplanDistance*grid.TileSize
+	if !p.valid || moved || navFrame-p.planned >= ReplanInterval || p.next >= len(p.points) {
+		p.plan(pos, goal, radius, mobility)
+	}
+	if !p.valid {
+		return rl.Vector2{}, false
+	}
+
+	// Skip waypoints that are reached or already visible further along
+	for p.next < len(p.points)-1 {
+		if rl.Vector2Distance(pos, p.points[p.next]) > float32(waypointReach) &&
+			!grid.LineClear(pos, p.points[p.next+1], radius, mobility) {
+			break
+		}
+		p.next++
+	}
+
+	return direction(pos, p.points[p.next]), true
+}
+
+func (p *Path) plan(pos, goal rl.Vector2, radius float32, mobility Mobility) {
+	p.goal = goal
+	p.planned = navFrame
+	points, ok := FindPath(pos, goal, radius, mobility)
+	p.valid = ok && len(points) > 0
+	p.points = points
+	p.next = 0
+	if len(points) > 1 {
+		p.next = 1 // first point is the tile we're standing on
+	}
+}
+
+func (p *Path) Reset() {
+	*p = Path{}
+}
+
+// Points returns the current route, mostly for debug drawing
+func (p *Path) Points() []rl.Vector2 {
+	if !p.valid {
+		return nil
+	}
+	return p.points[p.next:]
+}
+
+func direction(from, to rl.Vector2) rl.Vector2 {
+	d := rl.Vector2Subtract(to, from)
+	length := rl.Vector2Length(d)
+	if length == 0 {
+		return rl.Vector2{}
+	}
+	return rl.Vector2Scale(d, 1/length)
+}
@@ -0,0 +1,217 @@
+package pickup
+
+import (
+	"axelot/pkg/audio"
+	"axelot/pkg/fx"
+	"axelot/pkg/rng"
+	"fmt"
+	"math"
+	"sort"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Kind matches the item names used in the enemy loot tables
+type Kind string
+
+const (
+	Pearl        Kind = "pearl"         // currency
+	HealthBubble Kind = "health_bubble" // heals right away
+	DamageBoost  Kind = "damage_boost"  // temporary extra damage
+	SpeedShell   Kind = "speed_shell"   // temporary extra speed
+)
+
+type style struct {
+	color rl.Color
+	size  float32
+	label string // popup text on pickup
+}
+
+var styles = map[Kind]style{
+	Pearl:        {color: rl.NewColor(240, 240, 255, 255), size: 2, label: "+%d pearl"},
+	HealthBubble: {color: rl.NewColor(255, 110, 140, 255), size: 4, label: "+HP"},
+	DamageBoost:  {color: rl.NewColor(255, 170, 60, 255), size: 4, label: "Damage up!"},
+	SpeedShell:   {color: rl.NewColor(110, 230, 180, 255), size: 4, label: "Speed up!"},
+}
+
+type Pickup struct {
+	Kind   Kind
+	Amount int
+	Pos    rl.Vector2
+	Vel    rl.Vector2
+	Life   int
+	age    int
+}
+
+// popup is the floating text shown where something was collected
+type popup struct {
+	text  string
+	pos   rl.Vector2
+	color rl.Color
+	life  int
+}
+
+var (
+	MagnetRadius  float32 = 48 // pickups fly to the player inside this
+	CollectRadius float32 = 10
+	MagnetSpeed   float32 = 3.5
+	Lifetime      int     = 900 // frames before a pickup disappears
+	blinkTime     int     = 180 // starts blinking this long before it disappears
+	magnetDelay   int     = 20  // fresh drops scatter before the magnet grabs them
+	popupLife     int     = 45
+
+	// Fixed pool like the particles, when full new drops are skipped
+	pickups = make([]Pickup, 256)
+	count   int
+	popups  []popup
+)
+
+// Kinds lists every pickup kind, sorted
+func Kinds() []Kind {
+	kinds := make([]Kind, 0, len(styles))
+	for kind := range styles {
+		kinds = append(kinds, kind)
+	}
+	sort.Slice(kinds, func(i, j int) bool { return kinds[i] < kinds[j] })
+	return kinds
+}
+
+func IsKnown(kind Kind) bool {
+	_, ok := styles[kind]
+	return ok
+}
+
+// Spawn drops a pickup that pops out in a random direction
+func Spawn(kind Kind, pos rl.Vector2, amount int) bool {
+	if count >= len(pickups) || !IsKnown(kind) {
+		return false
+	}
+
+	angle := rng.Float64() * 2 * math.Pi
+	speed := 0.6 + rng.Float32()*0.8
+	pickups[count] = Pickup{
+		Kind:   kind,
+		Amount: amount,
+		Pos:    pos,
+		Vel:    rl.NewVector2(float32(math.Cos(angle))*speed, float32(math.Sin(angle))*speed),
+		Life:   Lifetime,
+	}
+	count++
+	return true
+}
+
+// UpdatePickups pulls pickups towards the collector, collect returns false when the item can't be used yet
+func UpdatePickups(collector rl.Vector2, collect func(kind Kind, amount int) bool) {
+	for i := 0; i < count; {
+		p := &pickups[i]
+		p.Life--
+		p.age++
+
+		dist := rl.Vector2Distance(p.Pos, collector)
+		if dist < MagnetRadius && p.age > magnetDelay {
+			// Magnet gets stronger the closer it is
+			pull := MagnetSpeed * (1 - dist/MagnetRadius*0.5)
+			p.Vel = rl.Vector2Scale(rl.Vector2Normalize(rl.Vector2Subtract(collector, p.Pos)), pull)
+		} else {
+			p.Vel = rl.Vector2Scale(p.Vel, 0.9)
+		}
+		p.Pos = rl.Vector2Add(p.Pos, p.Vel)
+
+		if dist < CollectRadius && collect(p.Kind, p.Amount) {
+			collected(p)
+			p.Life = 0
+		}
+
+		if p.Life <= 0 {
+			// Swap with the last live pickup, order doesn't matter
+			count--
+			pickups[i] = pickups[count]
+			continue
+		}
+		i++
+	}
+
+	alive := popups[:0]
+	for _, pop := range popups {
+		pop.life--
+		pop.pos.Y -= 0.4
+		if pop.life > 0 {
+			alive = append(alive, pop)
+		}
+	}
+	popups = alive
+}
+
+// collected plays the pickup feedback
+func collected(p *Pickup) {
+	s := styles[p.Kind]
+	sparkle := fx.PickupSparkle
+	sparkle.Colors = []rl.Color{s.color, rl.Fade(s.color, 0)}
+	fx.Burst(&sparkle, p.Pos.X, p.Pos.Y)
+	audio.PlayAt(audio.Pickup, p.Pos)
+
+	text := s.label
+	if p.Kind == Pearl {
+		text = fmt.Sprintf(s.label, p.Amount)
+	}
+	popups = append(popups, popup{text: text, pos: p.Pos, color: s.color, life: popupLife})
+}
+
+func DrawPickups() {
+	for i := 0; i < count; i++ {
+		p := &pickups[i]
+
+		// Blink before disappearing
+		if p.Life < blinkTime && (p.Life/8)%2 == 0 {
+			continue
+		}
+
+		s := styles[p.Kind]
+		bob := float32(math.Sin(float64(p.age)*0.1)) * 1.5
+		x, y := p.Pos.X, p.Pos.Y+bob
+		rl.DrawCircleV(rl.NewVector2(x, y), s.size+1, rl.Fade(s.color, 0.35))
+		rl.DrawCircleV(rl.NewVector2(x, y), s.size, s.color)
+		rl.DrawRectangle(int32(x-s.size/2), int32(y-s.size/2), 1, 1, rl.White) // highlight
+	}
+
+	for _, pop := range popups {
+		alpha := float32(pop.life) / float32(popupLife)
+		width := rl.MeasureText(pop.text, 8)
+		rl.DrawText(pop.text, int32(pop.pos.X)-width/2+1, int32(pop.pos.Y)-11, 8, rl.Fade(rl.Black, alpha*0.6))
+		rl.DrawText(pop.text, int32(pop.pos.X)-width/2, int32(pop.pos.Y)-12, 8, rl.Fade(pop.color, alpha))
+	}
+}
+
+// DrawPickupDebug shows the collect radius of every pickup
+func DrawPickupDebug() {
+	for i := 0; i < count; i++ {
+		rl.DrawCircleLinesV(pickups[i].Pos, CollectRadius, rl.Lime)
+	}
+}
+
+func ClearPickups() {
+	count = 0
+	popups = popups[:0]
+}
+
+func GetPickupCount() int {
+	return count
+}
+
+// Snapshot copies the pickups lying around, for save games
+func Snapshot() []Pickup {
+	return append([]Pickup(nil), pickups[:count]...)
+}
+
+// Restore replaces the pickups, restored ones can be magnetized right away
+func Restore(saved []Pickup) {
+	ClearPickups()
+	for _, p := range saved {
+		if count >= len(pickups) || !IsKnown(p.Kind) {
+			continue
+		}
+		p.age = magnetDelay + 1
+		pickups[count] = p
+		count++
+	}
+}
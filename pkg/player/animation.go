@@ -0,0 +1,163 @@
+package player
+
+import (
+	"axelot/pkg/anim"
+	"axelot/pkg/combat"
+	"axelot/pkg/fx"
+	"axelot/pkg/projectile"
+	"axelot/pkg/weapon"
+	"fmt"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Clip name suffixes for playerDir
+var dirNames = [4]string{"up", "down", "left", "right"}
+
+// strike is a basic attack waiting for the hit frame of the attack clip
+type strike struct {
+	weapon weapon.Weapon
+	damage float32
+}
+
+var (
+	animations *anim.Library
+	playerAnim anim.Animator
+
+	hurting       bool // plays the hurt clip once after a hit
+	pendingStrike *strike
+)
+
+// Used when assets/axolotl/animations.json can't be read, same rows as the sprite sheet
+func defaultAnimations() *anim.Library {
+	lib := anim.NewLibrary()
+	lib.Sheets["main"] = &anim.Sheet{Texture: "axolotl.sheet", FrameWidth: 32, FrameHeight: 32}
+	footsteps := []anim.Event{{Frame: 1, Name: "footstep"}, {Frame: 3, Name: "footstep"}}
+	for row, dir := range dirNames {
+		lib.Clips["idle_"+dir] = &anim.Clip{Sheet: "main", Row: row, Frames: 2, FPS: 1.33, Loop: true}
+		lib.Clips["walk_"+dir] = &anim.Clip{Sheet: "main", Row: row, Frames: 4, FPS: 7.5, Loop: true, Events: footsteps}
+	}
+	lib.Clips["attack"] = &anim.Clip{Sheet: "main", Row: 4, Frames: 4, FPS: 16, Events: []anim.Event{{Frame: 1, Name: "hit"}}}
+	lib.Clips["dash"] = &anim.Clip{Sheet: "main", Row: 4, Frames: 4, FPS: 12, Loop: true}
+	lib.Clips["charge"] = &anim.Clip{Sheet: "main", Row: 1, Frames: 1, Loop: true}
+	lib.Clips["hurt"] = &anim.Clip{Sheet: "main", Row: 5, Frames: 4, FPS: 12}
+
+	// Poses, each on its own sheet
+	poses := []struct {
+		name, texture string
+		frames        int
+		fps           float32
+		loop          bool
+	}{
+		{"death", "axolotl.death", 12, 10, false},
+		{"getting_up", "axolotl.getting_up", 12, 12, false},
+		{"rest", "axolotl.resting", 6, 8, false},
+		{"resting_idle", "axolotl.resting_idle", 12, 6, true},
+		{"floating", "axolotl.floating", 4, 6, true},
+		{"preparing_to_swim", "axolotl.preparing", 9, 24, false},
+	}
+	for _, p := range poses {
+		lib.Sheets[p.name] = &anim.Sheet{Texture: p.texture, FrameWidth: 32, FrameHeight: 32}
+		lib.Clips[p.name] = &anim.Clip{Sheet: p.name, Frames: p.frames, FPS: p.fps, Loop: p.loop}
+	}
+	lib.Link()
+	return lib
+}
+
+func initAnimations() {
+	lib, err := anim.LoadLibrary("assets/axolotl/animations.json")
+	if err != nil {
+		fmt.Println("animations:", err, "- using built-in clips")
+		lib = defaultAnimations()
+	}
+	animations = lib
+	animations.LoadTextures()
+	playerAnim = anim.NewAnimator(animations)
+	playerAnim.Play("idle_down")
+}
+
+// playerClip picks the clip for what the player is doing, most important first
+func playerClip() string {
+	if clip := poseClip(); clip != "" {
+		return clip
+	}
+	switch {
+	case isDashing:
+		return "dash"
+	case isAttacking:
+		return "attack"
+	case isCharging:
+		return "charge"
+	case hurting:
+		return "hurt"
+	case playerMoving:
+		return "walk_" + dirNames[playerDir]
+	case idleTicks >= floatAfter:
+		return "floating"
+	}
+	return "idle_" + dirNames[playerDir]
+}
+
+func updateAnimation() {
+	if hurting && playerAnim.Current() == "hurt" && playerAnim.Finished() {
+		hurting = false
+	}
+	updatePose()
+	playerAnim.Play(playerClip())
+	playerAnim.Update()
+
+	// The attack clip decides when the swing connects, an interrupted swing still lands
+	if pendingStrike != nil && (playerAnim.Fired("hit") || !isAttacking) {
+		releaseStrike()
+	}
+	if playerAnim.Fired("footstep") {
+		fx.Burst(&fx.Footstep, PlayerHitBox.X+PlayerHitBox.Width/2, PlayerHitBox.Y+PlayerHitBox.Height)
+	}
+}
+
+// startStrike queues a basic attack, clips without a hit frame strike right away
+func startStrike(w *weapon.Weapon, damage float32) {
+	pendingStrike = &strike{weapon: *w, damage: damage}
+	playerAnim.Restart("attack")
+	if !animations.HasEvent("attack", "hit") {
+		releaseStrike()
+	}
+}
+
+func releaseStrike() {
+	s := pendingStrike
+	pendingStrike = nil
+	center := GetPlayerCenter()
+	if s.weapon.Kind == weapon.Projectile {
+		projectile.Fire(s.weapon.Shot, projectile.PlayerFaction, center, facing, s.damage)
+		return
+	}
+	activeHitboxes = append(activeHitboxes, combat.NewArc(center, facing, s.weapon.Range, s.weapon.Arc, s.damage, attackHitFrames))
+}
+
+// SetPlayerDamageState plays the hurt clip, a new hit starts it over
+func SetPlayerDamageState() {
+	hurting = true
+	idleTicks = 0
+	if currentPose == poseResting || currentPose == poseWaking {
+		currentPose = poseNone
+	}
+	if playerAnim.Current() == "hurt" {
+		playerAnim.Restart("hurt")
+	}
+}
+
+func resetAnimation() {
+	hurting = false
+	pendingStrike = nil
+	resetPose()
+}
+
+func DrawPlayerTexture() {
+	tint := playerTint
+	// Flash while invulnerable
+	if invulnTimer > 0 && (invulnTimer/4)%2 == 0 {
+		tint = rl.Fade(playerTint, 0.35)
+	}
+	playerAnim.Draw(PlayerDest, tint)
+}
@@ -0,0 +1,101 @@
+package player
+
+import (
+	"axelot/pkg/console"
+	"axelot/pkg/pickup"
+	"fmt"
+	"strconv"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Ignores every hit while on, toggled with the god command
+var godMode bool
+
+func registerCommands() {
+	console.FloatVar("playerSpeed", "walking speed in pixels per frame", &playerSpeed)
+	console.FloatVar("sprintSpeed", "speed while sprinting", &sprintSpeed)
+	console.FloatVar("dashSpeed", "dash speed in pixels per frame", &dashSpeed)
+	console.IntVar("dashDuration", "frames a dash lasts", &dashDuration)
+	console.IntVar("attackCooldown", "frames between attacks, 0 uses the weapon's", &attackCooldown)
+	console.IntVar("invulnDuration", "i-frames after taking a hit", &invulnDuration)
+
+	console.Register(console.Command{
+		Name:  "god",
+		Help:  "toggle taking no damage",
+		Cheat: true,
+		Run: func(args []string) error {
+			godMode = !godMode
+			if godMode {
+				console.Printf("god mode on")
+			} else {
+				console.Printf("god mode off")
+			}
+			return nil
+		},
+	})
+
+	console.Register(console.Command{
+		Name:     "give",
+		Usage:    "<item> [amount]",
+		Help:     "give a pickup, or xp",
+		Cheat:    true,
+		Run:      give,
+		Complete: completeItems,
+	})
+
+	console.Register(console.Command{
+		Name:  "teleport",
+		Usage: "<x> <y>",
+		Help:  "move the player to a world position",
+		Cheat: true,
+		Run: func(args []string) error {
+			pos, err := console.ParseFloats(args, 2)
+			if err != nil {
+				return err
+			}
+			PlayerDest.X, PlayerDest.Y = pos[0], pos[1]
+			oldX, oldY = pos[0], pos[1]
+			Cam.Target = rl.NewVector2(float32(PlayerDest.X-(PlayerDest.Width/2)), float32(PlayerDest.Y-(PlayerDest.Height/2)))
+			return nil
+		},
+	})
+}
+
+func give(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing item")
+	}
+	amount := 1
+	if len(args) > 1 {
+		n, err := strconv.Atoi(args[1])
+		if err != nil || n <= 0 {
+			return fmt.Errorf("%q is not an amount", args[1])
+		}
+		amount = n
+	}
+
+	if args[0] == "xp" {
+		AddXP(amount)
+		return nil
+	}
+	kind := pickup.Kind(args[0])
+	if !pickup.IsKnown(kind) {
+		return fmt.Errorf("unknown item %q", args[0])
+	}
+	if !ApplyPickup(kind, amount) {
+		console.Printf("%s can't be used right now", kind)
+	}
+	return nil
+}
+
+func completeItems(arg int, prefix string) []string {
+	if arg != 0 {
+		return nil
+	}
+	items := []string{"xp"}
+	for _, kind := range pickup.Kinds() {
+		items = append(items, string(kind))
+	}
+	return items
+}
@@ -0,0 +1,21 @@
+package player
+
+import (
+	"axelot/pkg/pickup"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// DrawDebug shows the player's collision box, attack and magnet reach and live hitboxes
+func DrawDebug() {
+	rl.DrawRectangleLinesEx(PlayerHitBox, 1, rl.Green)
+
+	center := GetPlayerCenter()
+	rl.DrawCircleLinesV(center, GetEquippedWeapon().Range, rl.NewColor(0, 228, 48, 120))
+	rl.DrawLineV(center, rl.Vector2Add(center, rl.Vector2Scale(facing, 12)), rl.Green)
+	rl.DrawCircleLinesV(center, pickup.MagnetRadius, rl.NewColor(0, 158, 47, 60))
+
+	for _, hitbox := range activeHitboxes {
+		hitbox.DrawDebug(rl.Yellow)
+	}
+}
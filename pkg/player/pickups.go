@@ -0,0 +1,100 @@
+package player
+
+import (
+	"axelot/pkg/pickup"
+	"fmt"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+var (
+	pearls int
+
+	healthBubbleHeal float32 = 2
+
+	damageBoostTimer      int
+	damageBoostDuration   int     = 600
+	damageBoostMultiplier float32 = 1.5
+
+	speedBoostTimer      int
+	speedBoostDuration   int     = 600
+	speedBoostMultiplier float32 = 1.35
+)
+
+// ApplyPickup is called when the player touches a pickup, false leaves it lying around
+func ApplyPickup(kind pickup.Kind, amount int) bool {
+	switch kind {
+	case pickup.Pearl:
+		pearls += amount
+	case pickup.HealthBubble:
+		// Keep bubbles for later when already at full health
+		if currentHealth >= maxHealth {
+			return false
+		}
+		Heal(healthBubbleHeal * float32(amount))
+	case pickup.DamageBoost:
+		damageBoostTimer = damageBoostDuration
+	case pickup.SpeedShell:
+		speedBoostTimer = speedBoostDuration
+	default:
+		return false
+	}
+	return true
+}
+
+func Heal(amount float32) {
+	currentHealth += amount
+	if currentHealth > maxHealth {
+		currentHealth = maxHealth
+	}
+	UpdateHealthBar()
+}
+
+func updateBuffs() {
+	if damageBoostTimer > 0 {
+		damageBoostTimer--
+	}
+	if speedBoostTimer > 0 {
+		speedBoostTimer--
+	}
+}
+
+func resetBuffs() {
+	pearls = 0
+	damageBoostTimer = 0
+	speedBoostTimer = 0
+}
+
+// damageMultiplier combines upgrades with the temporary boost
+func damageMultiplier() float32 {
+	if damageBoostTimer > 0 {
+		return damageBonus * damageBoostMultiplier
+	}
+	return damageBonus
+}
+
+func speedMultiplier() float32 {
+	if speedBoostTimer > 0 {
+		return speedBonus * speedBoostMultiplier
+	}
+	return speedBonus
+}
+
+func GetPearls() int {
+	return pearls
+}
+
+// DrawPickupHUD shows pearls above the weapon slots, active boosts stack above them
+func DrawPickupHUD() {
+	y := int32(screenHeight - 94)
+	rl.DrawText(fmt.Sprintf("Pearls: %d", pearls), 10, y, 16, rl.NewColor(240, 240, 255, 255))
+
+	if damageBoostTimer > 0 {
+		y -= 18
+		rl.DrawText(fmt.Sprintf("Damage up %ds", damageBoostTimer/60+1), 10, y, 14, rl.NewColor(255, 170, 60, 255))
+	}
+	if speedBoostTimer > 0 {
+		y -= 18
+		rl.DrawText(fmt.Sprintf("Speed up %ds", speedBoostTimer/60+1), 10, y, 14, rl.NewColor(110, 230, 180, 255))
+	}
+}
@@ -1,9 +1,14 @@
 package player
 
 import (
+	"axelot/pkg/asset"
+	"axelot/pkg/audio"
+	"axelot/pkg/combat"
+	"axelot/pkg/fx"
+	"axelot/pkg/input"
+	"axelot/pkg/weapon"
 	"axelot/pkg/world"
 	"fmt"
-	"math"
 
 	rl "github.com/gen2brain/raylib-go/raylib"
 )
@@ -14,21 +19,20 @@ const (
 )
 
 var (
-	playerSprite rl.Texture2D
-	oldX, oldY   float32
+	oldX, oldY float32
 
-	playerSrc                                     rl.Rectangle
 	PlayerDest                                    rl.Rectangle
 	playerMoving                                  bool
 	playerDir                                     int
 	playerUp, playerDown, playerLeft, playerRight bool
-	playerFrame                                   int
 	PlayerHitBox                                  rl.Rectangle
 	playerHitBoxYOffset                           float32 = 3
 
 	frameCount int
 
 	playerSpeed float32 = 1.4
+	sprintSpeed float32 = 2
+	sprinting   bool
 
 	Cam rl.Camera2D
 
@@ -44,7 +48,7 @@ var (
 
 	// Combat system
 	lastAttackTime int
-	attackCooldown int     = 30
+	attackCooldown int     = 0 // frames, replaces the weapon cooldown when set from the console
 	attackRange    float32 = 40
 	isAttacking    bool
 	attackDuration int = 15
@@ -60,7 +64,6 @@ var (
 	chargeAttackPressed bool
 	chargeStartTime     int
 	isCharging          bool
-	maxChargeTime       int = 60
 
 	dashAttackPressed bool
 	isDashing         bool
@@ -69,41 +72,45 @@ var (
 	dashSpeed         float32 = 4.0
 	dashDirectionX    float32 = 0
 	dashDirectionY    float32 = 0
+	dashHitRadius     float32 = 12
+
+	// Last movement direction, attacks and dashes go this way
+	facing = rl.NewVector2(0, 1)
+
+	// Hitboxes of attacks in progress, they follow the player
+	activeHitboxes  []*combat.Hitbox
+	attackHitFrames int = 6
 
 	healthRegenTimer    int = 0
 	healthRegenInterval int = 120
 
+	// Hit response
+	invulnTimer    int
+	invulnDuration int = 60 // i-frames after taking a hit
+	knockback      combat.Impulse
+
 	slimeKillCount int = 0
 
 	// Visual effects
 	screenShake      float32 = 0
 	screenShakeDecay float32 = 0.9
-	chargeParticles  []ChargeParticle
 	chargeGlow       float32 = 0
+	chargeBubbles            = fx.NewEmitter(&fx.ChargeBubbles)
+	dashTrail                = fx.NewEmitter(&fx.DashTrail)
 )
 
-type ChargeParticle struct {
-	x, y    float32
-	vx, vy  float32
-	life    float32
-	maxLife float32
-	size    float32
-	color   rl.Color
-}
-
-// Weapon stats - just use one weapon for now
+// Weapons are loaded from data, the player carries every unlocked one and swaps with 1-3 or Tab
 var (
-	weaponDamage     float32 = 1.2
-	weaponRange      float32 = 40
-	weaponCooldown   int     = 30
-	weaponComboBonus float32 = 0.3
+	allWeapons     []weapon.Weapon
+	weapons        []weapon.Weapon
+	equippedWeapon int
+
+	// Cosmetic color from the profile
+	playerTint = rl.White
 )
 
 func InitPlayer() {
-	playerSprite = rl.LoadTexture("assets/axolotl/spritesheet.png")
-	healthBarTexture = rl.LoadTexture("assets/axolotl/Health_bar.png")
-
-	playerSrc = rl.NewRectangle(0, 0, 32, 32)
+	healthBarTexture = asset.Acquire("ui.player_health")
 
 	healthBarSrc = rl.NewRectangle(0, 0, 32, 64)
 
@@ -112,39 +119,75 @@ func InitPlayer() {
 
 	Cam = rl.NewCamera2D(rl.NewVector2(float32(screenWidth/2), float32(screenHeight/2)),
 		rl.NewVector2(float32(PlayerDest.X-(PlayerDest.Width/2)), float32(PlayerDest.Y-(PlayerDest.Height/2))), 0, 2)
-}
 
-func DrawPlayerTexture() {
-	rl.DrawTexturePro(playerSprite, playerSrc, PlayerDest, rl.NewVector2(0, 0), 0, rl.White)
+	loaded, err := weapon.LoadWeapons("assets/weapons.json")
+	if err != nil {
+		fmt.Println("weapons:", err, "- using built-in weapon")
+		loaded = weapon.DefaultWeapons
+	}
+	allWeapons = loaded
+	weapons = loaded
+	equippedWeapon = 0
+
+	// Before any upgrade, so every run starts from the same stats
+	baseMaxHealth = maxHealth
+	baseComboWindow = comboWindow
+	baseHealthRegenInterval = healthRegenInterval
+
+	initAnimations()
+	registerCommands()
 }
 
 func PlayerInput() {
-	if rl.IsKeyDown(rl.KeyW) || rl.IsKeyDown(rl.KeyUp) {
+	updateIdle()
+	if controlLocked() {
+		return
+	}
+
+	if input.Down(input.MoveUp) {
 		playerMoving = true
 		playerDir = 0
 		playerUp = true
 	}
 
-	if rl.IsKeyDown(rl.KeyS) || rl.IsKeyDown(rl.KeyDown) {
+	if input.Down(input.MoveDown) {
 		playerMoving = true
 		playerDir = 1
 		playerDown = true
 	}
 
-	if rl.IsKeyDown(rl.KeyA) || rl.IsKeyDown(rl.KeyLeft) {
+	if input.Down(input.MoveLeft) {
 		playerMoving = true
 		playerDir = 2
 		playerLeft = true
 	}
 
-	if rl.IsKeyDown(rl.KeyD) || rl.IsKeyDown(rl.KeyRight) {
+	if input.Down(input.MoveRight) {
 		playerMoving = true
 		playerDir = 3
 		playerRight = true
 	}
 
+	// Face the way we're moving, diagonals included
+	moveX, moveY := float32(0), float32(0)
+	if playerLeft {
+		moveX--
+	}
+	if playerRight {
+		moveX++
+	}
+	if playerUp {
+		moveY--
+	}
+	if playerDown {
+		moveY++
+	}
+	if moveX != 0 || moveY != 0 {
+		facing = rl.Vector2Normalize(rl.NewVector2(moveX, moveY))
+	}
+
 	// Basic attack - can interrupt charging
-	if rl.IsKeyPressed(rl.KeyQ) {
+	if input.Pressed(input.Attack) {
 		attackPressed = true
 		// Cancel charging if Q is pressed
 		if isCharging {
@@ -154,117 +197,142 @@ func PlayerInput() {
 	}
 
 	// Charge attack (hold E)
-	if rl.IsKeyPressed(rl.KeyE) && !isCharging && !isAttacking {
+	if input.Pressed(input.Charge) && !isCharging && !isAttacking {
 		isCharging = true
 		chargeStartTime = frameCount
 		chargeAttackPressed = false
 	}
-	if rl.IsKeyReleased(rl.KeyE) && isCharging {
+	if input.Released(input.Charge) && isCharging {
 		chargeAttackPressed = true
 		// Don't set isCharging to false here - let TryAttack handle it
 	}
 
 	// Dash attack (R key)
-	if rl.IsKeyPressed(rl.KeyR) {
+	if input.Pressed(input.Dash) {
 		dashAttackPressed = true
 	}
 
-	if rl.IsKeyDown(rl.KeyLeftShift) || rl.IsKeyDown(rl.KeyRightShift) {
-		playerSpeed = 2
-	} else {
-		playerSpeed = 1.4
+	// Weapon swap
+	for slot := 0; slot < len(weapons) && slot < 9; slot++ {
+		if input.Pressed(input.WeaponSlot(slot)) {
+			EquipWeapon(slot)
+		}
+	}
+	if input.Pressed(input.NextWeapon) {
+		EquipWeapon((equippedWeapon + 1) % len(weapons))
 	}
-}
 
-func TryAttack(targetPos rl.Vector2, attackFunc func(float32)) bool {
-	playerPos := rl.NewVector2(PlayerDest.X, PlayerDest.Y)
-	dist := rl.Vector2Distance(playerPos, targetPos)
+	sprinting = input.Down(input.Sprint)
+}
 
-	// Basic attack
-	if attackPressed && frameCount-lastAttackTime >= weaponCooldown && !isAttacking {
-		if dist <= weaponRange {
-			damage := weaponDamage
+func TryAttack() bool {
+	if controlLocked() {
+		attackPressed, chargeAttackPressed, dashAttackPressed = false, false, false
+		return false
+	}
 
-			// Combo system - more damage if attacking in sequence
-			if frameCount-lastComboTime <= comboWindow {
-				comboCount++
-				damage += weaponComboBonus * float32(comboCount)
-			} else {
-				comboCount = 1
-			}
+	center := GetPlayerCenter()
+	w := GetEquippedWeapon()
 
-			lastComboTime = frameCount
-			attackFunc(damage)
-			lastAttackTime = frameCount
-			isAttacking = true
-			attackTimer = attackDuration
-			playerDir = 4
-			attackPressed = false
-			return true
+	// Basic attack - an arc in front of the player
+	if attackPressed && frameCount-lastAttackTime >= attackCooldownFrames(w.Cooldown) && !isAttacking {
+		// Combo system - more damage if attacking in sequence
+		if frameCount-lastComboTime <= comboWindow {
+			comboCount++
+		} else {
+			comboCount = 1
 		}
+		startStrike(w, w.ComboDamage(comboCount)*damageMultiplier())
+		audio.Play(audio.Attack)
+		lastAttackTime = frameCount
+		isAttacking = true
+		attackTimer = attackDuration
+		attackPressed = false
+		return true
 	}
 
-	// Charge attack - only trigger when E is released
+	// Charge attack - only trigger when E is released, bursts in a circle
 	if chargeAttackPressed && !isAttacking && isCharging {
 		chargeTime := frameCount - chargeStartTime
-		if dist <= weaponRange*1.5 {
-			// Minimum charge time before it becomes effective
-			if chargeTime >= 15 {
-				chargeDamage := weaponDamage * (1.0 + float32(chargeTime)/float32(maxChargeTime)*1.5)
-				attackFunc(chargeDamage)
-			} else {
-				// Too quick, just do normal damage
-				attackFunc(weaponDamage)
-			}
+		radius := w.Range * w.Charge.RangeMultiplier * chargeRadiusScale
+		activeHitboxes = append(activeHitboxes, combat.NewCircle(center, radius, w.ChargeDamage(chargeTime)*damageMultiplier(), attackHitFrames))
 
-			isCharging = false
-			chargeAttackPressed = false
-			isAttacking = true
-			attackTimer = attackDuration + 5
-			playerDir = 4
-			lastAttackTime = frameCount
+		isCharging = false
+		chargeAttackPressed = false
+		isAttacking = true
+		attackTimer = attackDuration + 5
+		playerAnim.Restart("attack")
+		lastAttackTime = frameCount
 
-			// Water burst effect on charge release
-			SpawnChargeExplosion()
-			screenShake = 4.0
+		// Water burst effect on charge release
+		SpawnChargeExplosion()
+		audio.Play(audio.ChargeRelease)
+		screenShake = 4.0
 
-			return true
-		} else {
-			// Out of range, cancel charge
-			isCharging = false
-			chargeAttackPressed = false
-		}
+		return true
 	}
 
-	// Dash attack - water dash towards enemy
+	// Dash attack - water dash in the facing direction, hits everything along the way
 	if dashAttackPressed && !isDashing && !isAttacking && !isCharging {
-		if dist <= 120 {
-			isDashing = true
-			dashTimer = dashDuration
-			dashAttackPressed = false
+		isDashing = true
+		dashTimer = dashDuration
+		dashAttackPressed = false
 
-			// Calculate dash direction towards target
-			dashDirectionX = (targetPos.X - playerPos.X) / dist
-			dashDirectionY = (targetPos.Y - playerPos.Y) / dist
+		dashDirectionX = facing.X
+		dashDirectionY = facing.Y
 
-			// Do damage immediately on dash start (no knockback)
-			attackFunc(weaponDamage * 1.5)
+		activeHitboxes = append(activeHitboxes, combat.NewCapsule(center, center, dashHitRadius, w.Damage*w.DashMultiplier*damageMultiplier(), dashDuration))
 
-			// Spawn water wave effect
-			SpawnDashWave()
-			screenShake = 2.0
+		// Spawn water wave effect
+		SpawnDashWave()
+		audio.Play(audio.Dash)
+		screenShake = 2.0
 
-			return true
-		}
+		return true
 	}
 
 	attackPressed = false
+	dashAttackPressed = false
 	return false
 }
 
+// GetActiveHitboxes returns the player's attacks that can currently deal damage
+func GetActiveHitboxes() []*combat.Hitbox {
+	return activeHitboxes
+}
+
+// RegisterHit is called when an attack connects, it keeps the combo window open
+func RegisterHit() {
+	lastComboTime = frameCount
+}
+
+func GetPlayerCenter() rl.Vector2 {
+	return rl.NewVector2(PlayerDest.X+PlayerDest.Width/2, PlayerDest.Y+PlayerDest.Height/2)
+}
+
+func updateHitboxes() {
+	center := GetPlayerCenter()
+
+	alive := activeHitboxes[:0]
+	for _, hb := range activeHitboxes {
+		hb.Frames--
+		if !hb.Active() {
+			continue
+		}
+
+		// Arcs and bursts stay on the player, dash capsules stretch from the start point
+		if hb.Shape == combat.CapsuleShape {
+			hb.End = center
+		} else {
+			hb.Center = center
+		}
+		alive = append(alive, hb)
+	}
+	activeHitboxes = alive
+}
+
 func PlayerMoving() {
 	oldX, oldY = PlayerDest.X, PlayerDest.Y
-	playerSrc.X = playerSrc.Width * float32(playerFrame)
 
 	if isAttacking {
 		attackTimer--
@@ -282,12 +350,7 @@ func PlayerMoving() {
 			PlayerDest.Y += dashDirectionY * dashSpeed
 
 			// Spawn water trail particles
-			if frameCount%3 == 0 {
-				SpawnDashTrail()
-			}
-
-			// Set dash animation
-			playerDir = 4 // dash animation
+			fx.Emit(&dashTrail, PlayerDest.X+PlayerDest.Width/2, PlayerDest.Y+PlayerDest.Height/2, -dashDirectionX*2.0, -dashDirectionY*2.0)
 		} else {
 			isDashing = false
 			// Dash impact effect
@@ -300,20 +363,18 @@ func PlayerMoving() {
 	if isCharging && !isAttacking {
 		// Force player to stand still and face down
 		playerDir = 1
-		playerFrame = 0
 		playerMoving = false
 
 		// Update charge glow effect
 		chargeTime := frameCount - chargeStartTime
+		maxChargeTime := GetEquippedWeapon().Charge.MaxTime
 		chargeGlow = float32(chargeTime) / float32(maxChargeTime)
 		if chargeGlow > 1.0 {
 			chargeGlow = 1.0
 		}
 
 		// Spawn water bubbles around player
-		if frameCount%8 == 0 {
-			SpawnChargeParticle()
-		}
+		fx.Emit(&chargeBubbles, PlayerDest.X+PlayerDest.Width/2, PlayerDest.Y+PlayerDest.Height/2, 0, 0)
 
 		// Gentle water ripple effect when fully charged
 		if chargeTime >= maxChargeTime && frameCount%15 == 0 {
@@ -331,12 +392,16 @@ func PlayerMoving() {
 		}
 	}
 
-	// Update charge particles
-	UpdateChargeParticles()
-
-	RegenerateHealth()
+	// No regenerating back from the death animation
+	if !IsPlayerDead() {
+		RegenerateHealth()
+	}
+	updateBuffs()
 
-	currentSpeed := playerSpeed
+	currentSpeed := playerSpeed * speedMultiplier()
+	if sprinting {
+		currentSpeed = sprintSpeed * speedMultiplier()
+	}
 	if isDashing {
 		currentSpeed = dashSpeed
 	}
@@ -354,35 +419,34 @@ func PlayerMoving() {
 		if playerRight {
 			PlayerDest.X += currentSpeed
 		}
-
-		if frameCount%8 == 1 {
-			playerFrame++
-		}
-	} else if frameCount%45 == 1 && !isCharging {
-		playerFrame++
 	}
 
 	frameCount++
-	if playerFrame >= 8 {
-		playerFrame = 0
-	}
 
-	if !playerMoving && playerFrame > 1 {
-		playerFrame = 0
+	if invulnTimer > 0 {
+		invulnTimer--
 	}
 
-	playerSrc.Y = playerSrc.Height * float32(playerDir)
-	playerSrc.X = playerSrc.Width * float32(playerFrame)
+	if knockback.Active() {
+		dx, dy := knockback.Step()
+		PlayerDest.X += dx
+		PlayerDest.Y += dy
+	}
 
 	PlayerHitBox.X = PlayerDest.X + (PlayerDest.Width / 2) - PlayerHitBox.Width/2
 	PlayerHitBox.Y = PlayerDest.Y + (PlayerDest.Height / 2) + playerHitBoxYOffset
 
-	PlayerCollision(world.GroundTiles)
+	if PlayerCollision(world.GroundTiles) {
+		knockback.Stop()
+	}
+	updateHitboxes()
+	updateAnimation()
 
 	Cam.Target = rl.NewVector2(float32(PlayerDest.X-(PlayerDest.Width/2)), float32(PlayerDest.Y-(PlayerDest.Height/2)))
 
 	playerMoving = false
 	playerUp, playerDown, playerLeft, playerRight = false, false, false, false
+	sprinting = false
 
 }
 
@@ -419,8 +483,9 @@ func UpdateHealthBar() {
 	}
 }
 
-func PlayerCollision(tiles []world.Tile) {
+func PlayerCollision(tiles []world.Tile) bool {
 	var jsonMap = world.WorldMap
+	collided := false
 
 	for i := 0; i < len(tiles); i++ {
 		if PlayerHitBox.X < float32(tiles[i].X*jsonMap.TileSize+jsonMap.TileSize) &&
@@ -430,26 +495,49 @@ func PlayerCollision(tiles []world.Tile) {
 
 			PlayerDest.X = oldX
 			PlayerDest.Y = oldY
+			collided = true
 		}
 	}
+
+	return collided
 }
 
 func UnloadPlayerTexture() {
-	rl.UnloadTexture(playerSprite)
-	rl.UnloadTexture(healthBarTexture)
+	animations.UnloadTextures()
+	asset.Release("ui.player_health")
+}
+
+// TakeHit is damage from an enemy, it knocks the player back and grants i-frames
+func TakeHit(damage float32, source rl.Vector2) bool {
+	if invulnTimer > 0 || godMode || IsPlayerDead() {
+		return false
+	}
+
+	SetPlayerDamageState()
+	TakeDamage(damage)
+	knockback = combat.Knockback(source, GetPlayerCenter(), damage)
+	invulnTimer = invulnDuration
+	return true
 }
 
-func SetPlayerDamageState() {
-	playerDir = 5
+func IsInvulnerable() bool {
+	return invulnTimer > 0
 }
 
 func TakeDamage(damage float32) {
+	if currentHealth > 0 {
+		audio.Play(audio.PlayerDamage)
+	}
+
 	currentHealth -= damage
 	if currentHealth < 0 {
 		currentHealth = 0
 	}
 
 	UpdateHealthBar()
+	if IsPlayerDead() && currentPose != poseDying {
+		setPose(poseDying)
+	}
 }
 
 func DrawHealthBar() {
@@ -473,13 +561,15 @@ func IsPlayerDead() bool {
 }
 
 func ResetPlayer() {
+	resetProgression()
 	currentHealth = maxHealth
 	PlayerDest.X = 600
 	PlayerDest.Y = 400
 	playerDir = 1
-	playerFrame = 0
+	resetAnimation()
 	playerMoving = false
 	playerUp, playerDown, playerLeft, playerRight = false, false, false, false
+	sprinting = false
 	isAttacking = false
 	attackTimer = 0
 	attackPressed = false
@@ -487,6 +577,14 @@ func ResetPlayer() {
 	lastAttackTime = 0
 	healthRegenTimer = 0
 	slimeKillCount = 0
+	resetBuffs()
+
+	activeHitboxes = nil
+	facing = rl.NewVector2(0, 1)
+	invulnTimer = 0
+	knockback.Stop()
+	comboCount = 0
+	lastComboTime = 0
 
 	// Reset dash system
 	isDashing = false
@@ -501,8 +599,9 @@ func ResetPlayer() {
 	chargeStartTime = 0
 	chargeGlow = 0
 
-	// Clear all particles and effects
-	chargeParticles = []ChargeParticle{}
+	// Clear effects
+	chargeBubbles.Reset()
+	dashTrail.Reset()
 	screenShake = 0
 
 	Cam.Target = rl.NewVector2(float32(PlayerDest.X-(PlayerDest.Width/2)), float32(PlayerDest.Y-(PlayerDest.Height/2)))
@@ -523,222 +622,29 @@ func GetCurrentCombo() int {
 }
 
 func DrawKillCounter() {
-	killText := fmt.Sprintf("Jellyfish Killed: %d", slimeKillCount)
+	killText := fmt.Sprintf("Enemies Killed: %d", slimeKillCount)
 	rl.DrawText(killText, 10, 10, 20, rl.White)
 }
 
-func SpawnChargeParticle() {
-	// Spawn water bubble around player
-	distance := float32(rl.GetRandomValue(15, 35))
-
-	particle := ChargeParticle{
-		x:       PlayerDest.X + PlayerDest.Width/2 + float32(distance)*float32(rl.GetRandomValue(-1, 1)),
-		y:       PlayerDest.Y + PlayerDest.Height/2 + float32(distance)*float32(rl.GetRandomValue(-1, 1)),
-		vx:      float32(rl.GetRandomValue(-15, 15)) / 20.0, // Slower, more floaty
-		vy:      float32(rl.GetRandomValue(-25, -5)) / 15.0, // Bubbles rise up
-		life:    1.2,
-		maxLife: 1.2,
-		size:    float32(rl.GetRandomValue(3, 8)),
-		color:   rl.NewColor(100, uint8(150+rl.GetRandomValue(0, 105)), 255, 255), // Blue water bubbles
-	}
-
-	chargeParticles = append(chargeParticles, particle)
-}
-
-func SpawnChargeExplosion() {
-	// Spawn water splash in all directions
-	for i := 0; i < 15; i++ {
-		angle := float64(i) * 2.0 * math.Pi / 15.0
-		speed := float32(rl.GetRandomValue(30, 80)) / 10.0
-
-		particle := ChargeParticle{
-			x:       PlayerDest.X + PlayerDest.Width/2,
-			y:       PlayerDest.Y + PlayerDest.Height/2,
-			vx:      float32(math.Cos(angle)) * speed,
-			vy:      float32(math.Sin(angle)) * speed,
-			life:    1.5,
-			maxLife: 1.5,
-			size:    float32(rl.GetRandomValue(4, 10)),
-			color:   rl.NewColor(uint8(50+rl.GetRandomValue(0, 100)), uint8(200+rl.GetRandomValue(0, 55)), 255, 255), // Water splash
-		}
-
-		chargeParticles = append(chargeParticles, particle)
-	}
-}
-
-func SpawnDashWave() {
-	// Create expanding water wave with wave-like particles
-	for ring := 0; ring < 3; ring++ {
-		for i := 0; i < 16; i++ {
-			angle := float64(i) * 2.0 * math.Pi / 16.0
-			speed := float32(2+ring) * 1.8
-
-			// Add some wave-like irregularity
-			waveOffset := math.Sin(angle*4) * 0.3
-			finalSpeed := speed * (1.0 + float32(waveOffset))
-
-			particle := ChargeParticle{
-				x:       PlayerDest.X + PlayerDest.Width/2,
-				y:       PlayerDest.Y + PlayerDest.Height/2,
-				vx:      float32(math.Cos(angle)) * finalSpeed,
-				vy:      float32(math.Sin(angle)) * finalSpeed,
-				life:    2.2 - float32(ring)*0.4,
-				maxLife: 2.2 - float32(ring)*0.4,
-				size:    float32(3 + ring*3), // Bigger for better wave shapes
-				color:   rl.NewColor(80, 180+uint8(ring*20), 255, 180-uint8(ring*40)),
-			}
-
-			chargeParticles = append(chargeParticles, particle)
-		}
-	}
-}
-
-func SpawnDashTrail() {
-	// Water trail behind dashing player
-	for i := 0; i < 3; i++ {
-		particle := ChargeParticle{
-			x:       PlayerDest.X + PlayerDest.Width/2 + float32(rl.GetRandomValue(-8, 8)),
-			y:       PlayerDest.Y + PlayerDest.Height/2 + float32(rl.GetRandomValue(-8, 8)),
-			vx:      -dashDirectionX*2.0 + float32(rl.GetRandomValue(-10, 10))/20.0,
-			vy:      -dashDirectionY*2.0 + float32(rl.GetRandomValue(-10, 10))/20.0,
-			life:    0.8,
-			maxLife: 0.8,
-			size:    float32(rl.GetRandomValue(3, 7)),
-			color:   rl.NewColor(120, 200, 255, 180),
-		}
-
-		chargeParticles = append(chargeParticles, particle)
-	}
-}
-
-func SpawnDashImpact() {
-	// Water impact splash when dash ends
-	for i := 0; i < 12; i++ {
-		angle := float64(i) * 2.0 * math.Pi / 12.0
-		speed := float32(rl.GetRandomValue(20, 60)) / 10.0
-
-		particle := ChargeParticle{
-			x:       PlayerDest.X + PlayerDest.Width/2,
-			y:       PlayerDest.Y + PlayerDest.Height/2,
-			vx:      float32(math.Cos(angle)) * speed,
-			vy:      float32(math.Sin(angle))*speed - 1.0, // Slight upward bias
-			life:    1.2,
-			maxLife: 1.2,
-			size:    float32(rl.GetRandomValue(5, 12)),
-			color:   rl.NewColor(60, 220, 255, 255),
-		}
-
-		chargeParticles = append(chargeParticles, particle)
-	}
-}
-
-func UpdateChargeParticles() {
-	// Update existing particles
-	for i := len(chargeParticles) - 1; i >= 0; i-- {
-		p := &chargeParticles[i]
-		p.x += p.vx
-		p.y += p.vy
-		p.life -= 0.02
-
-		// Fade color
-		alpha := uint8(p.life * 255)
-		p.color.A = alpha
-
-		// Remove dead particles
-		if p.life <= 0 {
-			chargeParticles = append(chargeParticles[:i], chargeParticles[i+1:]...)
-		}
-	}
-}
-
 func DrawChargeEffects() {
 	// Draw pixel charge aura around player
 	if chargeGlow > 0 {
 		DrawPixelAura(int32(PlayerDest.X+PlayerDest.Width/2), int32(PlayerDest.Y+PlayerDest.Height/2), chargeGlow)
 	}
-
-	// Draw water particles with different shapes
-	for _, p := range chargeParticles {
-		DrawWaterParticle(p)
-	}
-}
-
-func DrawWaterParticle(p ChargeParticle) {
-	// Pixel-art water particles based on size and velocity
-	speed := math.Sqrt(float64(p.vx*p.vx + p.vy*p.vy))
-
-	if speed > 3.0 {
-		// Fast moving = pixel droplet
-		DrawPixelDroplet(p)
-	} else if p.size > 6 {
-		// Large = pixel splash
-		DrawPixelSplash(p)
-	} else {
-		// Small = pixel bubble
-		DrawPixelBubble(p)
-	}
 }
 
-func DrawPixelDroplet(p ChargeParticle) {
-	// Pixel droplet - hand-drawn pixel pattern
-	x, y := int32(p.x), int32(p.y)
-
-	// Main droplet body (3x4 pixel pattern)
-	rl.DrawRectangle(x-1, y-1, 3, 2, p.color)
-	rl.DrawRectangle(x, y-2, 1, 1, p.color)
-	rl.DrawRectangle(x-1, y+1, 3, 1, p.color)
-
-	// Droplet tail (1x2 pixels behind)
-	angle := math.Atan2(float64(p.vy), float64(p.vx))
-	tailX := x - int32(math.Cos(angle)*4)
-	tailY := y - int32(math.Sin(angle)*4)
-
-	fadeColor := p.color
-	fadeColor.A = fadeColor.A / 2
-	rl.DrawRectangle(tailX, tailY, 1, 2, fadeColor)
+func SpawnChargeExplosion() {
+	fx.Burst(&fx.WaterBurst, PlayerDest.X+PlayerDest.Width/2, PlayerDest.Y+PlayerDest.Height/2)
 }
 
-func DrawPixelSplash(p ChargeParticle) {
-	// Pixel splash - scattered pixel pattern
-	x, y := int32(p.x), int32(p.y)
-
-	// Main splash body
-	rl.DrawRectangle(x-2, y-1, 5, 3, p.color)
-	rl.DrawRectangle(x-1, y-2, 3, 1, p.color)
-	rl.DrawRectangle(x-1, y+2, 3, 1, p.color)
-
-	// Scattered droplets around splash
-	fadeColor := p.color
-	fadeColor.A = fadeColor.A / 2
-
-	rl.DrawRectangle(x-4, y, 1, 1, fadeColor)
-	rl.DrawRectangle(x+4, y-1, 1, 1, fadeColor)
-	rl.DrawRectangle(x, y-4, 1, 1, fadeColor)
-	rl.DrawRectangle(x-1, y+4, 1, 1, fadeColor)
+func SpawnDashWave() {
+	for i := range fx.DashWave {
+		fx.Burst(&fx.DashWave[i], PlayerDest.X+PlayerDest.Width/2, PlayerDest.Y+PlayerDest.Height/2)
+	}
 }
 
-func DrawPixelBubble(p ChargeParticle) {
-	// Pixel bubble - simple but clean
-	x, y := int32(p.x), int32(p.y)
-	size := int32(p.size)
-
-	if size <= 3 {
-		// Small bubble (2x2)
-		rl.DrawRectangle(x, y, 2, 2, p.color)
-		// Highlight pixel
-		highlight := rl.NewColor(255, 255, 255, p.color.A/2)
-		rl.DrawRectangle(x, y, 1, 1, highlight)
-	} else {
-		// Medium bubble (3x3)
-		rl.DrawRectangle(x-1, y-1, 3, 3, p.color)
-		rl.DrawRectangle(x, y-2, 1, 1, p.color)
-		rl.DrawRectangle(x-2, y, 1, 1, p.color)
-
-		// Highlight pixels
-		highlight := rl.NewColor(255, 255, 255, p.color.A/2)
-		rl.DrawRectangle(x-1, y-1, 1, 1, highlight)
-		rl.DrawRectangle(x, y-1, 1, 1, highlight)
-	}
+func SpawnDashImpact() {
+	fx.Burst(&fx.DashImpact, PlayerDest.X+PlayerDest.Width/2, PlayerDest.Y+PlayerDest.Height/2)
 }
 
 func DrawPixelAura(centerX, centerY int32, intensity float32) {
@@ -791,7 +697,56 @@ func GetScreenShakeOffset() rl.Vector2 {
 	return rl.NewVector2(shakeX, shakeY)
 }
 
+func GetEquippedWeapon() *weapon.Weapon {
+	return &weapons[equippedWeapon]
+}
+
+// SetLockedWeapons leaves the named weapons behind, the first weapon is always kept
+func SetLockedWeapons(locked map[string]bool) {
+	weapons = nil
+	for i, w := range allWeapons {
+		if i == 0 || !locked[w.Name] {
+			weapons = append(weapons, w)
+		}
+	}
+	equippedWeapon = 0
+}
+
+func SetPlayerColor(color rl.Color) {
+	playerTint = color
+}
+
+func EquipWeapon(slot int) {
+	if slot < 0 || slot >= len(weapons) || slot == equippedWeapon {
+		return
+	}
+
+	equippedWeapon = slot
+
+	// Swapping drops the current charge and combo
+	isCharging = false
+	chargeAttackPressed = false
+	comboCount = 0
+}
+
 func DrawWeaponHUD() {
+	w := GetEquippedWeapon()
+
+	// Weapon slots, active one highlighted in its color
+	for i := range weapons {
+		slotX := int32(10 + i*28)
+		slotY := int32(screenHeight - 70)
+		color := rl.NewColor(60, 60, 60, 180)
+		if i == equippedWeapon {
+			color = weapons[i].Tint()
+		}
+		rl.DrawRectangle(slotX, slotY, 24, 24, color)
+		rl.DrawRectangleLines(slotX, slotY, 24, 24, rl.White)
+		rl.DrawText(fmt.Sprintf("%d", i+1), slotX+8, slotY+5, 14, rl.White)
+	}
+	weaponText := fmt.Sprintf("%s (%s)", w.Name, w.Kind)
+	rl.DrawText(weaponText, int32(10+len(weapons)*28+6), int32(screenHeight-65), 16, w.Tint())
+
 	// Combo counter
 	if comboCount > 1 {
 		comboText := fmt.Sprintf("Combo x%d", comboCount)
@@ -801,7 +756,7 @@ func DrawWeaponHUD() {
 	// Charge indicator
 	if isCharging {
 		chargeTime := frameCount - chargeStartTime
-		chargePercent := float32(chargeTime) / float32(maxChargeTime)
+		chargePercent := float32(chargeTime) / float32(w.Charge.MaxTime)
 		if chargePercent > 1.0 {
 			chargePercent = 1.0
 		}
@@ -817,7 +772,7 @@ func DrawWeaponHUD() {
 
 		// Water charge progress - changes color when effective
 		var barColor rl.Color
-		if chargeTime >= 15 {
+		if chargeTime >= w.Charge.MinTime {
 			barColor = rl.NewColor(100, 220, 255, 200) // Bright water blue
 		} else {
 			barColor = rl.NewColor(150, 180, 220, 180) // Light blue building up
@@ -825,7 +780,7 @@ func DrawWeaponHUD() {
 		rl.DrawRectangle(int32(barX), int32(barY), int32(barWidth*chargePercent), int32(barHeight), barColor)
 
 		// Text
-		if chargeTime >= 15 {
+		if chargeTime >= w.Charge.MinTime {
 			rl.DrawText("WATER POWER READY!", 10, 85, 12, rl.NewColor(100, 220, 255, 255))
 		} else {
 			rl.DrawText("Gathering water energy...", 10, 85, 12, rl.NewColor(150, 180, 220, 255))
@@ -833,5 +788,5 @@ func DrawWeaponHUD() {
 	}
 
 	// Controls reminder
-	rl.DrawText("Controls: Q-Attack, E-Charge, R-Dash", 10, screenHeight-25, 12, rl.Gray)
+	rl.DrawText("Controls: Q-Attack, E-Charge, R-Dash, 1-3/Tab-Weapon", 10, screenHeight-25, 12, rl.Gray)
 }
@@ -0,0 +1,109 @@
+package player
+
+import (
+	"axelot/pkg/input"
+)
+
+// pose is a whole-body animation that takes over from normal play
+type pose int
+
+const (
+	poseNone      pose = iota
+	poseGettingUp      // start of a run, no control until the axolotl stands
+	poseResting        // lies down after a while without input
+	poseWaking         // gets ready to swim again before control comes back
+	poseDying          // plays out before the game over screen
+)
+
+var (
+	currentPose pose
+	idleTicks   int // ticks without any input
+	deathTimer  int // ticks since the death clip ended
+
+	floatAfter = 180 // ticks without input before the axolotl floats in place
+	restAfter  = 600 // and before it lies down
+	deathHold  = 45  // ticks the last death frame stays before game over
+)
+
+func setPose(p pose) {
+	currentPose = p
+	switch p {
+	case poseGettingUp:
+		playerAnim.Restart("getting_up")
+	case poseResting:
+		playerAnim.Restart("rest")
+	case poseWaking:
+		playerAnim.Restart("preparing_to_swim")
+	case poseDying:
+		playerAnim.Restart("death")
+		deathTimer = 0
+		isCharging = false
+		chargeAttackPressed = false
+		pendingStrike = nil
+	}
+}
+
+// controlLocked is true while getting up, waking or dying, input is ignored then
+func controlLocked() bool {
+	return currentPose == poseGettingUp || currentPose == poseWaking || currentPose == poseDying
+}
+
+// updateIdle wakes a resting player on any input and counts the ticks without one
+func updateIdle() {
+	if input.Current() == 0 {
+		idleTicks++
+		return
+	}
+	idleTicks = 0
+	if currentPose == poseResting {
+		setPose(poseWaking)
+	}
+}
+
+// updatePose moves between poses, called before the clip is picked
+func updatePose() {
+	switch currentPose {
+	case poseGettingUp, poseWaking:
+		if playerAnim.Finished() {
+			currentPose = poseNone
+		}
+	case poseDying:
+		if playerAnim.Finished() {
+			deathTimer++
+		}
+	case poseNone:
+		if idleTicks >= restAfter && !isCharging && !hurting {
+			setPose(poseResting)
+		}
+	}
+}
+
+// poseClip is the clip of the current pose, empty when the normal clips play
+func poseClip() string {
+	switch currentPose {
+	case poseDying:
+		return "death"
+	case poseGettingUp:
+		return "getting_up"
+	case poseWaking:
+		return "preparing_to_swim"
+	case poseResting:
+		// Lie down once, then breathe
+		if playerAnim.Current() == "resting_idle" || (playerAnim.Current() == "rest" && playerAnim.Finished()) {
+			return "resting_idle"
+		}
+		return "rest"
+	}
+	return ""
+}
+
+// DeathFinished is true once the death animation has played out
+func DeathFinished() bool {
+	return currentPose == poseDying && deathTimer >= deathHold
+}
+
+func resetPose() {
+	idleTicks = 0
+	deathTimer = 0
+	setPose(poseGettingUp)
+}
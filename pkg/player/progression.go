@@ -0,0 +1,130 @@
+package player
+
+import (
+	"axelot/pkg/upgrade"
+	"fmt"
+	"math"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+var (
+	level           int = 1
+	xp              int
+	pendingLevelUps int
+
+	xpBase   float32 = 6    // xp needed for level 2
+	xpGrowth float32 = 1.35 // every level needs this much more
+
+	// Values upgrades start from at the beginning of a run, copied from player.go by InitPlayer
+	baseMaxHealth           float32
+	baseComboWindow         int
+	baseHealthRegenInterval int
+
+	// Multipliers from upgrades
+	damageBonus       float32 = 1
+	cooldownScale     float32 = 1
+	chargeRadiusScale float32 = 1
+	speedBonus        float32 = 1
+)
+
+// XPToNextLevel is the xp needed to go from the current level to the next
+func XPToNextLevel() int {
+	return int(math.Round(float64(xpBase) * math.Pow(float64(xpGrowth), float64(level-1))))
+}
+
+// AddXP is called for every kill, level ups queue until an upgrade is picked for each
+func AddXP(amount int) {
+	xp += amount
+	for xp >= XPToNextLevel() {
+		xp -= XPToNextLevel()
+		level++
+		pendingLevelUps++
+	}
+}
+
+func HasPendingLevelUp() bool {
+	return pendingLevelUps > 0
+}
+
+func PendingLevelUps() int {
+	return pendingLevelUps
+}
+
+// SkipLevelUp uses up a level up without an upgrade, when every upgrade is maxed
+func SkipLevelUp() {
+	if pendingLevelUps > 0 {
+		pendingLevelUps--
+	}
+}
+
+// ApplyUpgrade uses up a pending level up on def
+func ApplyUpgrade(def *upgrade.Def) {
+	upgrade.Take(def)
+	SkipLevelUp()
+	ApplyBonus(def)
+}
+
+// ApplyBonus changes the stat without counting a stack, used for permanent unlocks
+func ApplyBonus(def *upgrade.Def) {
+	switch def.Stat {
+	case upgrade.Damage:
+		damageBonus = def.Apply(damageBonus)
+	case upgrade.Cooldown:
+		cooldownScale = def.Apply(cooldownScale)
+	case upgrade.ComboWindow:
+		comboWindow = int(def.Apply(float32(comboWindow)))
+	case upgrade.ChargeRadius:
+		chargeRadiusScale = def.Apply(chargeRadiusScale)
+	case upgrade.MaxHealth:
+		old := maxHealth
+		maxHealth = def.Apply(maxHealth)
+		Heal(maxHealth - old)
+	case upgrade.RegenInterval:
+		healthRegenInterval = int(def.Apply(float32(healthRegenInterval)))
+		if healthRegenInterval < 10 {
+			healthRegenInterval = 10
+		}
+	case upgrade.Speed:
+		speedBonus = def.Apply(speedBonus)
+	}
+}
+
+func resetProgression() {
+	level = 1
+	xp = 0
+	pendingLevelUps = 0
+
+	maxHealth = baseMaxHealth
+	comboWindow = baseComboWindow
+	healthRegenInterval = baseHealthRegenInterval
+	damageBonus = 1
+	cooldownScale = 1
+	chargeRadiusScale = 1
+	speedBonus = 1
+
+	upgrade.ResetUpgrades()
+}
+
+func GetLevel() int {
+	return level
+}
+
+// attackCooldownFrames is the equipped weapon's cooldown after upgrades
+func attackCooldownFrames(cooldown int) int {
+	if attackCooldown > 0 {
+		cooldown = attackCooldown
+	}
+	return int(float32(cooldown) * cooldownScale)
+}
+
+// DrawXPBar draws the level and progress along the bottom of the screen
+func DrawXPBar() {
+	barX, barY := int32(10), int32(screenHeight-16)
+	barWidth, barHeight := int32(500), int32(6)
+
+	progress := float32(xp) / float32(XPToNextLevel())
+	rl.DrawRectangle(barX, barY, barWidth, barHeight, rl.NewColor(20, 20, 40, 180))
+	rl.DrawRectangle(barX, barY, int32(float32(barWidth)*progress), barHeight, rl.NewColor(120, 230, 140, 255))
+	rl.DrawText(fmt.Sprintf("Lv %d", level), barX, barY-16, 14, rl.White)
+}
@@ -0,0 +1,129 @@
+package player
+
+import (
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Snapshot is the player part of a save game
+type Snapshot struct {
+	X       float32 `json:"x"`
+	Y       float32 `json:"y"`
+	Dir     int     `json:"dir"`
+	FacingX float32 `json:"facingX"`
+	FacingY float32 `json:"facingY"`
+
+	Health    float32 `json:"health"`
+	MaxHealth float32 `json:"maxHealth"`
+	Weapon    string  `json:"weapon"`
+
+	FrameCount     int `json:"frameCount"`
+	LastAttackTime int `json:"lastAttackTime"`
+	Combo          int `json:"combo"`
+	LastComboTime  int `json:"lastComboTime"`
+	InvulnTimer    int `json:"invulnTimer"`
+	RegenTimer     int `json:"regenTimer"`
+	Kills          int `json:"kills"`
+
+	Level           int `json:"level"`
+	XP              int `json:"xp"`
+	PendingLevelUps int `json:"pendingLevelUps"`
+
+	// Stats after upgrades, restored as they are instead of replaying the upgrades
+	DamageBonus       float32 `json:"damageBonus"`
+	CooldownScale     float32 `json:"cooldownScale"`
+	ChargeRadiusScale float32 `json:"chargeRadiusScale"`
+	SpeedBonus        float32 `json:"speedBonus"`
+	ComboWindow       int     `json:"comboWindow"`
+	RegenInterval     int     `json:"regenInterval"`
+
+	Pearls           int `json:"pearls"`
+	DamageBoostTimer int `json:"damageBoostTimer"`
+	SpeedBoostTimer  int `json:"speedBoostTimer"`
+}
+
+func TakeSnapshot() Snapshot {
+	return Snapshot{
+		X:       PlayerDest.X,
+		Y:       PlayerDest.Y,
+		Dir:     playerDir,
+		FacingX: facing.X,
+		FacingY: facing.Y,
+
+		Health:    currentHealth,
+		MaxHealth: maxHealth,
+		Weapon:    GetEquippedWeapon().Name,
+
+		FrameCount:     frameCount,
+		LastAttackTime: lastAttackTime,
+		Combo:          comboCount,
+		LastComboTime:  lastComboTime,
+		InvulnTimer:    invulnTimer,
+		RegenTimer:     healthRegenTimer,
+		Kills:          slimeKillCount,
+
+		Level:           level,
+		XP:              xp,
+		PendingLevelUps: pendingLevelUps,
+
+		DamageBonus:       damageBonus,
+		CooldownScale:     cooldownScale,
+		ChargeRadiusScale: chargeRadiusScale,
+		SpeedBonus:        speedBonus,
+		ComboWindow:       comboWindow,
+		RegenInterval:     healthRegenInterval,
+
+		Pearls:           pearls,
+		DamageBoostTimer: damageBoostTimer,
+		SpeedBoostTimer:  speedBoostTimer,
+	}
+}
+
+// RestoreSnapshot puts the player back, weapons have to be unlocked with SetLockedWeapons first
+func RestoreSnapshot(snap Snapshot) {
+	ResetPlayer()
+
+	PlayerDest.X = snap.X
+	PlayerDest.Y = snap.Y
+	oldX, oldY = snap.X, snap.Y
+	playerDir = snap.Dir
+	facing = rl.NewVector2(snap.FacingX, snap.FacingY)
+
+	maxHealth = snap.MaxHealth
+	currentHealth = snap.Health
+	for i := range weapons {
+		if weapons[i].Name == snap.Weapon {
+			equippedWeapon = i
+		}
+	}
+
+	frameCount = snap.FrameCount
+	lastAttackTime = snap.LastAttackTime
+	comboCount = snap.Combo
+	lastComboTime = snap.LastComboTime
+	invulnTimer = snap.InvulnTimer
+	healthRegenTimer = snap.RegenTimer
+	slimeKillCount = snap.Kills
+
+	level = snap.Level
+	xp = snap.XP
+	pendingLevelUps = snap.PendingLevelUps
+
+	damageBonus = snap.DamageBonus
+	cooldownScale = snap.CooldownScale
+	chargeRadiusScale = snap.ChargeRadiusScale
+	speedBonus = snap.SpeedBonus
+	comboWindow = snap.ComboWindow
+	healthRegenInterval = snap.RegenInterval
+
+	pearls = snap.Pearls
+	damageBoostTimer = snap.DamageBoostTimer
+	speedBoostTimer = snap.SpeedBoostTimer
+
+	Cam.Target = rl.NewVector2(float32(PlayerDest.X-(PlayerDest.Width/2)), float32(PlayerDest.Y-(PlayerDest.Height/2)))
+	UpdateHealthBar()
+
+	// Saved mid-death the animation plays again, otherwise the run resumes by getting up
+	if IsPlayerDead() {
+		setPose(poseDying)
+	}
+}
@@ -0,0 +1,129 @@
+package profile
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"axelot/pkg/storage"
+)
+
+// Version is bumped when the meaning of a field changes, newer files still load
+const Version = 1
+
+// Profile is what carries over between runs
+type Profile struct {
+	Version int            `json:"version"`
+	Shells  int            `json:"shells"`  // currency for unlocks
+	Unlocks map[string]int `json:"unlocks"` // unlock id -> level bought
+	Color   string         `json:"color"`   // equipped color unlock, empty = default
+	Runs    int            `json:"runs"`
+
+	// Fields written by a newer version of the game, saved back untouched
+	extra map[string]json.RawMessage
+}
+
+// profileFields avoids recursing into the custom (un)marshalers
+type profileFields Profile
+
+func (p *Profile) UnmarshalJSON(data []byte) error {
+	var fields profileFields
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	for _, known := range []string{"version", "shells", "unlocks", "color", "runs"} {
+		delete(raw, known)
+	}
+
+	*p = Profile(fields)
+	p.extra = raw
+	return nil
+}
+
+func (p Profile) MarshalJSON() ([]byte, error) {
+	known, err := json.Marshal(profileFields(p))
+	if err != nil {
+		return nil, err
+	}
+	if len(p.extra) == 0 {
+		return known, nil
+	}
+
+	var merged map[string]json.RawMessage
+	if err := json.Unmarshal(known, &merged); err != nil {
+		return nil, err
+	}
+	for key, value := range p.extra {
+		if _, ok := merged[key]; !ok {
+			merged[key] = value
+		}
+	}
+	return json.Marshal(merged)
+}
+
+var (
+	current  = newProfile()
+	savePath = storage.Path("profile.json")
+)
+
+func newProfile() *Profile {
+	return &Profile{Version: Version, Unlocks: map[string]int{}}
+}
+
+// InitProfile loads the unlock catalog and the saved profile
+func InitProfile(unlockFile string) {
+	if err := LoadUnlocks(unlockFile); err != nil {
+		fmt.Println("unlocks:", err, "- no unlocks available")
+	}
+	if err := Load(); err != nil {
+		fmt.Println("profile:", err, "- starting a new profile")
+	}
+}
+
+// Load reads the profile, a broken file is moved aside so it isn't overwritten
+func Load() error {
+	current = newProfile()
+
+	data, err := os.ReadFile(savePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	loaded := newProfile()
+	if err := json.Unmarshal(data, loaded); err != nil {
+		storage.Quarantine(savePath)
+		return fmt.Errorf("%s: %w", savePath, err)
+	}
+	if loaded.Unlocks == nil {
+		loaded.Unlocks = map[string]int{}
+	}
+	if loaded.Version > Version {
+		fmt.Printf("profile: saved by a newer version (%d), keeping unknown fields\n", loaded.Version)
+	}
+	current = loaded
+	return nil
+}
+
+func Save() error {
+	// Never downgrade the version a newer game wrote
+	if current.Version < Version {
+		current.Version = Version
+	}
+	data, err := json.MarshalIndent(current, "", "  ")
+	if err != nil {
+		return err
+	}
+	return storage.WriteFileAtomic(savePath, data)
+}
+
+func Get() *Profile {
+	return current
+}
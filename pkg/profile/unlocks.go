@@ -0,0 +1,240 @@
+package profile
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"axelot/pkg/asset"
+	"axelot/pkg/upgrade"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+type Kind string
+
+const (
+	UpgradeUnlock Kind = "upgrade" // permanent stat bonus, one stack per level
+	WeaponUnlock  Kind = "weapon"  // the weapon is only carried once bought
+	ColorUnlock   Kind = "color"   // cosmetic tint for the axolotl
+)
+
+// Unlock is one entry of the unlock menu, loaded from assets/unlocks.json
+type Unlock struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Kind        Kind   `json:"kind"`
+	Costs       []int  `json:"costs"` // price of each level, empty = free
+
+	// Upgrade unlocks
+	Stat   upgrade.Stat `json:"stat,omitempty"`
+	Op     upgrade.Op   `json:"op,omitempty"`
+	Amount float32      `json:"amount,omitempty"`
+
+	Weapon string   `json:"weapon,omitempty"`
+	Color  [3]uint8 `json:"color,omitempty"`
+}
+
+// Reward turns a finished run into shells
+type Reward struct {
+	PerKill      float32 `json:"perKill"`
+	PerMinute    float32 `json:"perMinute"`
+	PerCombo     float32 `json:"perCombo"` // for the best combo of the run
+	VictoryBonus int     `json:"victoryBonus"`
+}
+
+type unlockFile struct {
+	Reward  Reward   `json:"reward"`
+	Unlocks []Unlock `json:"unlocks"`
+}
+
+var (
+	unlocks []Unlock
+	reward  = Reward{PerKill: 1, PerMinute: 5, PerCombo: 2}
+)
+
+func LoadUnlocks(file string) error {
+	byteValue, err := asset.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	var data unlockFile
+	if err := json.Unmarshal(byteValue, &data); err != nil {
+		return fmt.Errorf("%s: %w", file, err)
+	}
+
+	ids := map[string]bool{}
+	for i := range data.Unlocks {
+		u := &data.Unlocks[i]
+		if u.ID == "" || ids[u.ID] {
+			return fmt.Errorf("%s: unlock %d: missing or duplicate id", file, i)
+		}
+		ids[u.ID] = true
+		if err := u.validate(); err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+	}
+
+	unlocks = data.Unlocks
+	reward = data.Reward
+	return nil
+}
+
+func (u *Unlock) validate() error {
+	switch u.Kind {
+	case UpgradeUnlock:
+		op, err := upgrade.CheckEffect(u.Stat, u.Op, u.Amount)
+		if err != nil {
+			return fmt.Errorf("%s: %w", u.ID, err)
+		}
+		u.Op = op
+		if len(u.Costs) == 0 {
+			return fmt.Errorf("%s: upgrades need at least one cost", u.ID)
+		}
+	case WeaponUnlock:
+		if u.Weapon == "" {
+			return fmt.Errorf("%s: missing weapon name", u.ID)
+		}
+	case ColorUnlock:
+	default:
+		return fmt.Errorf("%s: unknown kind %q", u.ID, u.Kind)
+	}
+	return nil
+}
+
+func Unlocks() []Unlock {
+	return unlocks
+}
+
+func findUnlock(id string) *Unlock {
+	for i := range unlocks {
+		if unlocks[i].ID == id {
+			return &unlocks[i]
+		}
+	}
+	return nil
+}
+
+func Level(id string) int {
+	return current.Unlocks[id]
+}
+
+// Owned is whether at least one level was bought, free unlocks are always owned
+func (u *Unlock) Owned() bool {
+	return len(u.Costs) == 0 || Level(u.ID) > 0
+}
+
+func (u *Unlock) MaxLevel() int {
+	return len(u.Costs)
+}
+
+// NextCost is the price of the next level, false when fully bought
+func (u *Unlock) NextCost() (int, bool) {
+	level := Level(u.ID)
+	if level >= len(u.Costs) {
+		return 0, false
+	}
+	return u.Costs[level], true
+}
+
+func (u *Unlock) Equipped() bool {
+	return u.Kind == ColorUnlock && current.Color == u.ID
+}
+
+func (u *Unlock) Tint() rl.Color {
+	return rl.NewColor(u.Color[0], u.Color[1], u.Color[2], 255)
+}
+
+// Buy buys the next level of an unlock, owned colors get equipped instead
+func Buy(id string) error {
+	u := findUnlock(id)
+	if u == nil {
+		return fmt.Errorf("unknown unlock %q", id)
+	}
+
+	if u.Kind == ColorUnlock && u.Owned() {
+		current.Color = u.ID
+		return Save()
+	}
+
+	cost, ok := u.NextCost()
+	if !ok {
+		return fmt.Errorf("%s is maxed out", u.Name)
+	}
+	if current.Shells < cost {
+		return fmt.Errorf("not enough shells")
+	}
+
+	current.Shells -= cost
+	current.Unlocks[u.ID]++
+	if u.Kind == ColorUnlock {
+		current.Color = u.ID
+	}
+	return Save()
+}
+
+// RunReward is how many shells a run is worth
+func RunReward(kills, frames, maxCombo int, victory bool) int {
+	shells := reward.PerKill*float32(kills) + reward.PerMinute*float32(frames)/3600 + reward.PerCombo*float32(maxCombo)
+	if victory {
+		shells += float32(reward.VictoryBonus)
+	}
+	return int(shells)
+}
+
+// AwardRun banks the reward of a finished run and saves
+func AwardRun(kills, frames, maxCombo int, victory bool) int {
+	earned := RunReward(kills, frames, maxCombo, victory)
+	current.Shells += earned
+	current.Runs++
+	if err := Save(); err != nil {
+		fmt.Println("profile:", err)
+	}
+	return earned
+}
+
+// Loadout is a copy of the unlock levels a run starts with, replays store it
+func Loadout() map[string]int {
+	levels := make(map[string]int, len(current.Unlocks))
+	for id, level := range current.Unlocks {
+		levels[id] = level
+	}
+	return levels
+}
+
+// Bonuses are the permanent upgrades of a loadout, one entry per level
+func Bonuses(levels map[string]int) []*upgrade.Def {
+	var bonuses []*upgrade.Def
+	for i := range unlocks {
+		u := &unlocks[i]
+		if u.Kind != UpgradeUnlock {
+			continue
+		}
+		def := &upgrade.Def{ID: u.ID, Name: u.Name, Stat: u.Stat, Op: u.Op, Amount: u.Amount}
+		for level := 0; level < levels[u.ID]; level++ {
+			bonuses = append(bonuses, def)
+		}
+	}
+	return bonuses
+}
+
+// LockedWeapons are weapons with an unlock the loadout hasn't bought
+func LockedWeapons(levels map[string]int) map[string]bool {
+	locked := map[string]bool{}
+	for i := range unlocks {
+		if unlocks[i].Kind == WeaponUnlock && len(unlocks[i].Costs) > 0 && levels[unlocks[i].ID] == 0 {
+			locked[unlocks[i].Weapon] = true
+		}
+	}
+	return locked
+}
+
+// PlayerColor is the equipped color, false keeps the sprite's own colors
+func PlayerColor() (rl.Color, bool) {
+	u := findUnlock(current.Color)
+	if u == nil || u.Kind != ColorUnlock || !u.Owned() {
+		return rl.White, false
+	}
+	return u.Tint(), true
+}
@@ -0,0 +1,21 @@
+package projectile
+
+// Pufferfish spines, speed comes from the enemy's attack
+var Spine = Def{
+	Style:    SpineStyle,
+	Speed:    2.2,
+	Radius:   4,
+	Lifetime: 120,
+	Color:    [3]uint8{255, 230, 120},
+}
+
+// The axolotl's water bolt, curves a little towards the closest enemy
+var WaterBolt = Def{
+	Style:       BoltStyle,
+	Speed:       4,
+	Radius:      4,
+	Lifetime:    40,
+	Homing:      0.06,
+	HomingRange: 80,
+	Color:       [3]uint8{120, 210, 255},
+}
@@ -0,0 +1,243 @@
+package projectile
+
+import (
+	"axelot/pkg/combat"
+	"axelot/pkg/fx"
+	"axelot/pkg/nav"
+	"fmt"
+	"math"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Faction decides who a projectile can hurt, projectiles never hit their own side
+type Faction int
+
+const (
+	PlayerFaction Faction = iota
+	EnemyFaction
+)
+
+type Style string
+
+const (
+	SpineStyle  Style = "spine"  // thin line along the flight direction
+	BoltStyle   Style = "bolt"   // glowing water ball with a bubble trail
+	BubbleStyle Style = "bubble" // slow hollow bubble
+)
+
+// Def describes a projectile type, weapons and enemies embed it in their data files
+type Def struct {
+	Style       Style    `json:"style"`
+	Speed       float32  `json:"speed"`                 // pixels per frame
+	Radius      float32  `json:"radius"`                // collision radius
+	Lifetime    int      `json:"lifetime"`              // frames
+	Homing      float32  `json:"homing,omitempty"`      // max turn per frame in radians, 0 flies straight
+	HomingRange float32  `json:"homingRange,omitempty"` // how far it looks for a target
+	Pierce      int      `json:"pierce,omitempty"`      // extra targets it passes through
+	IgnoreTiles bool     `json:"ignoreTiles,omitempty"` // flies over islands
+	Color       [3]uint8 `json:"color"`
+}
+
+func (d *Def) Validate() error {
+	switch d.Style {
+	case SpineStyle, BoltStyle, BubbleStyle:
+	case "":
+		d.Style = BoltStyle
+	default:
+		return fmt.Errorf("unknown projectile style %q", d.Style)
+	}
+	if d.Speed <= 0 || d.Lifetime <= 0 {
+		return fmt.Errorf("projectile speed and lifetime must be positive")
+	}
+	if d.Radius <= 0 {
+		d.Radius = 3
+	}
+	return nil
+}
+
+func (d *Def) Tint() rl.Color {
+	return rl.NewColor(d.Color[0], d.Color[1], d.Color[2], 255)
+}
+
+type Projectile struct {
+	Pos     rl.Vector2
+	Vel     rl.Vector2
+	Faction Faction
+	Life    int
+	Def     *Def
+
+	// Hitbox moves with the projectile, its hit list stops a piercing shot hitting the same target twice
+	Hitbox combat.Hitbox
+
+	pierceLeft int
+	trail      fx.Emitter
+}
+
+// Target is one side of the fight, registered once by the game
+type Target struct {
+	Faction Faction
+	// Hit resolves a projectile against this side, true means it connected and is used up
+	Hit func(p *Projectile) bool
+	// Nearest finds something to home in on within maxDist
+	Nearest func(from rl.Vector2, maxDist float32) (rl.Vector2, bool)
+}
+
+var (
+	// Pooled like particles, when full new shots are dropped
+	pool    = make([]Projectile, 512)
+	count   int
+	targets []Target
+)
+
+func RegisterTarget(t Target) {
+	targets = append(targets, t)
+}
+
+// Fire launches a projectile from `from` towards dir, returns false when the pool is full
+func Fire(def *Def, faction Faction, from, dir rl.Vector2, damage float32) bool {
+	if count >= len(pool) {
+		return false
+	}
+
+	dir = rl.Vector2Normalize(dir)
+	if rl.Vector2Length(dir) == 0 {
+		dir = rl.NewVector2(1, 0)
+	}
+
+	p := &pool[count]
+	*p = Projectile{
+		Pos:        from,
+		Vel:        rl.Vector2Scale(dir, def.Speed),
+		Faction:    faction,
+		Life:       def.Lifetime,
+		Def:        def,
+		Hitbox:     *combat.NewCircle(from, def.Radius, damage, def.Lifetime),
+		pierceLeft: def.Pierce,
+	}
+	if def.Style == BoltStyle {
+		p.trail = fx.NewEmitter(&fx.BoltTrail)
+	}
+	count++
+	return true
+}
+
+func UpdateProjectiles() {
+	for i := 0; i < count; {
+		if pool[i].step() {
+			i++
+			continue
+		}
+		// Swap with the last live projectile, order doesn't matter
+		count--
+		pool[i] = pool[count]
+	}
+}
+
+// step moves the projectile one frame and reports whether it is still alive
+func (p *Projectile) step() bool {
+	p.Life--
+	if p.Life <= 0 {
+		return false
+	}
+
+	if p.Def.Homing > 0 {
+		p.steer()
+	}
+
+	p.Pos = rl.Vector2Add(p.Pos, p.Vel)
+	p.Hitbox.Center = p.Pos
+	if p.trail.Def != nil {
+		fx.Emit(&p.trail, p.Pos.X, p.Pos.Y, -p.Vel.X*0.2, -p.Vel.Y*0.2)
+	}
+
+	if !p.Def.IgnoreTiles && !nav.GetGrid().WalkableAt(p.Pos, nav.Swimmer) {
+		if p.Def.Style == BoltStyle {
+			fx.Burst(&fx.BoltSplash, p.Pos.X, p.Pos.Y)
+		}
+		return false
+	}
+
+	for _, t := range targets {
+		if t.Faction == p.Faction || t.Hit == nil {
+			continue
+		}
+		if t.Hit(p) {
+			if p.Def.Style == BoltStyle {
+				fx.Burst(&fx.BoltSplash, p.Pos.X, p.Pos.Y)
+			}
+			if p.pierceLeft == 0 {
+				return false
+			}
+			p.pierceLeft--
+		}
+	}
+	return true
+}
+
+// steer turns the velocity towards the nearest hostile target, limited by Def.Homing
+func (p *Projectile) steer() {
+	best := rl.Vector2{}
+	bestDist := float32(math.MaxFloat32)
+	for _, t := range targets {
+		if t.Faction == p.Faction || t.Nearest == nil {
+			continue
+		}
+		if pos, ok := t.Nearest(p.Pos, p.Def.HomingRange); ok {
+			if d := rl.Vector2Distance(p.Pos, pos); d < bestDist {
+				best, bestDist = pos, d
+			}
+		}
+	}
+	if bestDist == float32(math.MaxFloat32) {
+		return
+	}
+
+	current := math.Atan2(float64(p.Vel.Y), float64(p.Vel.X))
+	wanted := math.Atan2(float64(best.Y-p.Pos.Y), float64(best.X-p.Pos.X))
+	diff := math.Remainder(wanted-current, 2*math.Pi)
+	limit := float64(p.Def.Homing)
+	if diff > limit {
+		diff = limit
+	} else if diff < -limit {
+		diff = -limit
+	}
+
+	angle := current + diff
+	speed := rl.Vector2Length(p.Vel)
+	p.Vel = rl.NewVector2(float32(math.Cos(angle))*speed, float32(math.Sin(angle))*speed)
+}
+
+func DrawProjectiles() {
+	for i := 0; i < count; i++ {
+		p := &pool[i]
+		color := p.Def.Tint()
+		switch p.Def.Style {
+		case SpineStyle:
+			tail := rl.Vector2Subtract(p.Pos, rl.Vector2Scale(p.Vel, 2))
+			rl.DrawLineEx(tail, p.Pos, 2, color)
+		case BoltStyle:
+			rl.DrawCircleV(p.Pos, p.Def.Radius+1, rl.Fade(color, 0.4))
+			rl.DrawCircleV(p.Pos, p.Def.Radius-1, color)
+			rl.DrawRectangle(int32(p.Pos.X)-1, int32(p.Pos.Y)-1, 1, 1, rl.White) // highlight
+		case BubbleStyle:
+			rl.DrawCircleLinesV(p.Pos, p.Def.Radius, color)
+			rl.DrawRectangle(int32(p.Pos.X-p.Def.Radius/2), int32(p.Pos.Y-p.Def.Radius/2), 1, 1, rl.White)
+		}
+	}
+}
+
+// DrawProjectileDebug outlines the hitbox of every projectile in flight
+func DrawProjectileDebug() {
+	for i := 0; i < count; i++ {
+		pool[i].Hitbox.DrawDebug(rl.Orange)
+	}
+}
+
+func ClearProjectiles() {
+	count = 0
+}
+
+func GetProjectileCount() int {
+	return count
+}
@@ -0,0 +1,272 @@
+package replay
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"os"
+	"runtime/debug"
+
+	"axelot/pkg/asset"
+	"axelot/pkg/input"
+	"axelot/pkg/storage"
+)
+
+// Version of the file format, older or newer files are refused
+const Version = 1
+
+var magic = [4]byte{'A', 'X', 'R', 'P'}
+
+var (
+	ErrNotReplay = errors.New("not a replay file")
+	ErrCorrupt   = errors.New("replay checksum mismatch")
+	ErrVersion   = errors.New("unsupported replay version")
+)
+
+type EventKind uint8
+
+const (
+	UpgradePick EventKind = iota // value is the chosen card
+)
+
+// Event is a choice made outside of the per-tick input, e.g. on the level up screen
+type Event struct {
+	Tick  int
+	Kind  EventKind
+	Value int
+}
+
+// Checksum is a hash of the game state at a tick, playback compares against it to find desyncs
+type Checksum struct {
+	Tick  int
+	Value uint32
+}
+
+type Replay struct {
+	Build   string         // game build and data the run was recorded with
+	Seed    int64          // rng seed of the run
+	Loadout map[string]int // unlock levels the run started with
+	Inputs  []input.State  // one per tick
+	Events  []Event
+	Sums    []Checksum
+}
+
+var (
+	// Ticks between state checksums
+	ChecksumInterval = 60
+
+	// Build identifies this binary and its data, set once at startup
+	Build = "dev"
+)
+
+// ComputeBuild hashes the VCS revision and the gameplay data files
+func ComputeBuild(files ...string) string {
+	h := sha256.New()
+	if info, ok := debug.ReadBuildInfo(); ok {
+		h.Write([]byte(info.Main.Version))
+		for _, setting := range info.Settings {
+			if setting.Key == "vcs.revision" || setting.Key == "vcs.modified" {
+				h.Write([]byte(setting.Value))
+			}
+		}
+	}
+	for _, file := range files {
+		data, _ := asset.ReadFile(file)
+		h.Write(data)
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+func Path(name string) string {
+	return storage.Path("replays/" + name)
+}
+
+// Encode writes the replay as header, run-length encoded inputs, events, checksums and a CRC32
+func Encode(r *Replay) ([]byte, error) {
+	loadout, err := json.Marshal(r.Loadout)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := append([]byte(nil), magic[:]...)
+	buf = binary.LittleEndian.AppendUint16(buf, Version)
+	buf = appendBytes(buf, []byte(r.Build))
+	buf = binary.AppendVarint(buf, r.Seed)
+	buf = appendBytes(buf, loadout)
+	buf = binary.AppendUvarint(buf, uint64(len(r.Inputs)))
+
+	// Inputs barely change from tick to tick, store runs of equal states
+	var runs [][2]uint64
+	for i, s := range r.Inputs {
+		if i > 0 && input.State(runs[len(runs)-1][0]) == s {
+			runs[len(runs)-1][1]++
+			continue
+		}
+		runs = append(runs, [2]uint64{uint64(s), 1})
+	}
+	buf = binary.AppendUvarint(buf, uint64(len(runs)))
+	for _, run := range runs {
+		buf = binary.AppendUvarint(buf, run[0])
+		buf = binary.AppendUvarint(buf, run[1])
+	}
+
+	buf = binary.AppendUvarint(buf, uint64(len(r.Events)))
+	for _, ev := range r.Events {
+		buf = binary.AppendUvarint(buf, uint64(ev.Tick))
+		buf = append(buf, byte(ev.Kind))
+		buf = binary.AppendUvarint(buf, uint64(ev.Value))
+	}
+
+	buf = binary.AppendUvarint(buf, uint64(len(r.Sums)))
+	for _, sum := range r.Sums {
+		buf = binary.AppendUvarint(buf, uint64(sum.Tick))
+		buf = binary.LittleEndian.AppendUint32(buf, sum.Value)
+	}
+
+	return binary.LittleEndian.AppendUint32(buf, crc32.ChecksumIEEE(buf)), nil
+}
+
+func appendBytes(buf, data []byte) []byte {
+	buf = binary.AppendUvarint(buf, uint64(len(data)))
+	return append(buf, data...)
+}
+
+func Decode(data []byte) (*Replay, error) {
+	if len(data) < len(magic)+6 || !bytes.Equal(data[:len(magic)], magic[:]) {
+		return nil, ErrNotReplay
+	}
+	body, sum := data[:len(data)-4], binary.LittleEndian.Uint32(data[len(data)-4:])
+	if crc32.ChecksumIEEE(body) != sum {
+		return nil, ErrCorrupt
+	}
+	if v := binary.LittleEndian.Uint16(body[4:]); v != Version {
+		return nil, fmt.Errorf("%w: %d", ErrVersion, v)
+	}
+
+	rd := reader{data: body[6:]}
+	r := &Replay{}
+	r.Build = string(rd.bytes())
+	r.Seed = rd.varint()
+	if err := json.Unmarshal(rd.bytes(), &r.Loadout); err != nil && rd.err == nil {
+		rd.err = err
+	}
+
+	ticks := rd.uvarint()
+	runs := rd.uvarint()
+	for i := uint64(0); i < runs && rd.err == nil; i++ {
+		state, length := input.State(rd.uvarint()), rd.uvarint()
+		if uint64(len(r.Inputs))+length > ticks {
+			rd.err = ErrCorrupt
+			break
+		}
+		for j := uint64(0); j < length; j++ {
+			r.Inputs = append(r.Inputs, state)
+		}
+	}
+	if rd.err == nil && uint64(len(r.Inputs)) != ticks {
+		rd.err = errors.New("tick count mismatch")
+	}
+
+	events := rd.uvarint()
+	for i := uint64(0); i < events && rd.err == nil; i++ {
+		r.Events = append(r.Events, Event{Tick: int(rd.uvarint()), Kind: EventKind(rd.byte()), Value: int(rd.uvarint())})
+	}
+
+	sums := rd.uvarint()
+	for i := uint64(0); i < sums && rd.err == nil; i++ {
+		r.Sums = append(r.Sums, Checksum{Tick: int(rd.uvarint()), Value: rd.uint32()})
+	}
+
+	if rd.err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCorrupt, rd.err)
+	}
+	return r, nil
+}
+
+func Save(path string, r *Replay) error {
+	data, err := Encode(r)
+	if err != nil {
+		return err
+	}
+	return storage.WriteFileAtomic(path, data)
+}
+
+func Load(path string) (*Replay, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	r, err := Decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return r, nil
+}
+
+// reader keeps the first error so decoding can check once at the end
+type reader struct {
+	data []byte
+	err  error
+}
+
+func (rd *reader) uvarint() uint64 {
+	if rd.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(rd.data)
+	if n <= 0 {
+		rd.err = errors.New("truncated")
+		return 0
+	}
+	rd.data = rd.data[n:]
+	return v
+}
+
+func (rd *reader) varint() int64 {
+	if rd.err != nil {
+		return 0
+	}
+	v, n := binary.Varint(rd.data)
+	if n <= 0 {
+		rd.err = errors.New("truncated")
+		return 0
+	}
+	rd.data = rd.data[n:]
+	return v
+}
+
+func (rd *reader) byte() byte {
+	if rd.err != nil || len(rd.data) < 1 {
+		rd.err = errors.New("truncated")
+		return 0
+	}
+	b := rd.data[0]
+	rd.data = rd.data[1:]
+	return b
+}
+
+func (rd *reader) uint32() uint32 {
+	if rd.err != nil || len(rd.data) < 4 {
+		rd.err = errors.New("truncated")
+		return 0
+	}
+	v := binary.LittleEndian.Uint32(rd.data)
+	rd.data = rd.data[4:]
+	return v
+}
+
+func (rd *reader) bytes() []byte {
+	n := rd.uvarint()
+	if rd.err != nil || uint64(len(rd.data)) < n {
+		rd.err = errors.New("truncated")
+		return nil
+	}
+	b := rd.data[:n]
+	rd.data = rd.data[n:]
+	return b
+}
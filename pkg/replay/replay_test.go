@@ -0,0 +1,125 @@
+package replay
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"reflect"
+	"testing"
+
+	"axelot/pkg/input"
+)
+
+func sample() *Replay {
+	return &Replay{
+		Build:   "test-build",
+		Seed:    -1234567,
+		Loadout: map[string]int{"vitality": 2, "trident": 1},
+		Inputs: []input.State{
+			0, 0, 0,
+			input.MoveUp, input.MoveUp | input.Attack,
+			input.MoveUp, input.MoveUp,
+			0,
+		},
+		Events: []Event{{Tick: 4, Kind: UpgradePick, Value: 2}},
+		Sums:   []Checksum{{Tick: 0, Value: 0xdeadbeef}, {Tick: 60, Value: 42}},
+	}
+}
+
+// reseal puts a valid CRC back on an edited file, so Decode gets past the checksum
+func reseal(data []byte) []byte {
+	body := data[:len(data)-4]
+	return binary.LittleEndian.AppendUint32(append([]byte(nil), body...), crc32.ChecksumIEEE(body))
+}
+
+func encode(t *testing.T, r *Replay) []byte {
+	t.Helper()
+	data, err := Encode(r)
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	return data
+}
+
+func TestRoundTrip(t *testing.T) {
+	want := sample()
+	got, err := Decode(encode(t, want))
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("round trip\n got %+v\nwant %+v", got, want)
+	}
+}
+
+func TestRoundTripEmpty(t *testing.T) {
+	got, err := Decode(encode(t, &Replay{Build: "dev"}))
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if got.Build != "dev" || len(got.Inputs) != 0 || len(got.Events) != 0 || len(got.Sums) != 0 {
+		t.Fatalf("empty replay decoded as %+v", got)
+	}
+}
+
+func TestFlippedByte(t *testing.T) {
+	data := encode(t, sample())
+	for i := len(magic); i < len(data); i++ {
+		flipped := append([]byte(nil), data...)
+		flipped[i] ^= 0x10
+		if _, err := Decode(flipped); !errors.Is(err, ErrCorrupt) {
+			t.Fatalf("byte %d flipped: got %v, want ErrCorrupt", i, err)
+		}
+	}
+}
+
+func TestVersion(t *testing.T) {
+	data := encode(t, sample())
+	binary.LittleEndian.PutUint16(data[len(magic):], Version+1)
+	if _, err := Decode(reseal(data)); !errors.Is(err, ErrVersion) {
+		t.Fatalf("got %v, want ErrVersion", err)
+	}
+}
+
+func TestNotReplay(t *testing.T) {
+	data := encode(t, sample())
+	data[0] = 'X'
+	if _, err := Decode(data); !errors.Is(err, ErrNotReplay) {
+		t.Fatalf("got %v, want ErrNotReplay", err)
+	}
+	if _, err := Decode(data[:5]); !errors.Is(err, ErrNotReplay) {
+		t.Fatalf("5 bytes: got %v, want ErrNotReplay", err)
+	}
+}
+
+func TestTruncated(t *testing.T) {
+	data := encode(t, sample())
+	for n := len(magic) + 6; n < len(data); n++ {
+		if _, err := Decode(data[:n]); err == nil {
+			t.Fatalf("cut to %d bytes: decoded without an error", n)
+		}
+		// Even with a valid checksum the missing data is noticed
+		if _, err := Decode(reseal(data[:n])); !errors.Is(err, ErrCorrupt) {
+			t.Fatalf("cut to %d bytes and resealed: got %v, want ErrCorrupt", n, err)
+		}
+	}
+}
+
+func TestRunLongerThanTicks(t *testing.T) {
+	buf := append([]byte(nil), magic[:]...)
+	buf = binary.LittleEndian.AppendUint16(buf, Version)
+	buf = appendBytes(buf, []byte("dev"))
+	buf = binary.AppendVarint(buf, 1)
+	buf = appendBytes(buf, []byte("{}"))
+	buf = binary.AppendUvarint(buf, 2) // ticks
+	buf = binary.AppendUvarint(buf, 1) // runs
+	buf = binary.AppendUvarint(buf, uint64(input.MoveUp))
+	buf = binary.AppendUvarint(buf, 1<<40) // far more than two ticks
+	buf = binary.AppendUvarint(buf, 0)     // events
+	buf = binary.AppendUvarint(buf, 0)     // checksums
+	buf = binary.LittleEndian.AppendUint32(buf, crc32.ChecksumIEEE(buf))
+
+	if _, err := Decode(buf); !errors.Is(err, ErrCorrupt) {
+		t.Fatalf("got %v, want ErrCorrupt", err)
+	}
+}
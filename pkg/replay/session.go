@@ -0,0 +1,228 @@
+package replay
+
+import (
+	"axelot/pkg/input"
+	"fmt"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Playback speeds picked with 1/2/3 while watching
+var Speeds = []int{1, 2, 4}
+
+var (
+	recording *Replay
+	playback  *Replay
+
+	// Playback position
+	cursor     int // ticks played so far
+	eventIndex int
+	sumIndex   int
+	paused     bool
+	speed      = 1
+	seekTarget = -1 // tick to fast-forward to, -1 when not seeking
+	seekBudget = 600
+
+	desyncTick = -1
+)
+
+// StartRecording begins a new recording, the previous one is dropped
+func StartRecording(seed int64, loadout map[string]int) {
+	recording = &Replay{Build: Build, Seed: seed, Loadout: loadout}
+}
+
+// StopRecording returns the finished recording
+func StopRecording() *Replay {
+	r := recording
+	recording = nil
+	return r
+}
+
+func IsRecording() bool {
+	return recording != nil
+}
+
+// RecordTick stores the input of the tick about to run, with a state checksum every ChecksumInterval ticks
+func RecordTick(state input.State, checksum func() uint32) {
+	if recording == nil {
+		return
+	}
+	tick := len(recording.Inputs)
+	if tick%ChecksumInterval == 0 {
+		recording.Sums = append(recording.Sums, Checksum{Tick: tick, Value: checksum()})
+	}
+	recording.Inputs = append(recording.Inputs, state)
+}
+
+func RecordEvent(kind EventKind, value int) {
+	if recording == nil {
+		return
+	}
+	recording.Events = append(recording.Events, Event{Tick: len(recording.Inputs), Kind: kind, Value: value})
+}
+
+// StartPlayback plays r from the first tick, the caller restarts the run with its seed and loadout
+func StartPlayback(r *Replay) {
+	playback = r
+	recording = nil
+	rewind()
+	paused = false
+	speed = 1
+	seekTarget = -1
+}
+
+func rewind() {
+	cursor = 0
+	eventIndex = 0
+	sumIndex = 0
+	desyncTick = -1
+}
+
+func StopPlayback() {
+	playback = nil
+}
+
+func IsPlaying() bool {
+	return playback != nil
+}
+
+func Current() *Replay {
+	return playback
+}
+
+func Tick() int {
+	return cursor
+}
+
+// NextInput is the input of the next tick, false at the end of the replay.
+// The checksum is compared before the tick runs, like it was taken while recording.
+func NextInput(checksum func() uint32) (input.State, bool) {
+	if playback == nil || cursor >= len(playback.Inputs) {
+		return 0, false
+	}
+	if sumIndex < len(playback.Sums) && playback.Sums[sumIndex].Tick == cursor {
+		if desyncTick < 0 && playback.Sums[sumIndex].Value != checksum() {
+			desyncTick = cursor
+		}
+		sumIndex++
+	}
+	state := playback.Inputs[cursor]
+	cursor++
+	return state, true
+}
+
+// NextEvent hands out recorded choices in the order they were made
+func NextEvent(kind EventKind) (int, bool) {
+	if playback == nil {
+		return 0, false
+	}
+	for eventIndex < len(playback.Events) {
+		ev := playback.Events[eventIndex]
+		eventIndex++
+		if ev.Kind == kind {
+			return ev.Value, true
+		}
+	}
+	return 0, false
+}
+
+func Finished() bool {
+	return playback != nil && cursor >= len(playback.Inputs)
+}
+
+// Desynced reports the first tick where the state stopped matching the recording
+func Desynced() (int, bool) {
+	return desyncTick, desyncTick >= 0
+}
+
+func BuildMismatch() bool {
+	return playback != nil && playback.Build != Build
+}
+
+func TogglePause() {
+	paused = !paused
+}
+
+func SetSpeed(s int) {
+	speed = s
+}
+
+// Seek jumps to a tick, true when the run has to restart from the beginning first
+func Seek(tick int) bool {
+	if playback == nil {
+		return false
+	}
+	tick = max(0, min(tick, len(playback.Inputs)))
+	seekTarget = tick
+	if tick < cursor {
+		rewind()
+		return true
+	}
+	return false
+}
+
+func IsSeeking() bool {
+	return seekTarget >= 0
+}
+
+// StepsThisFrame is how many ticks to simulate this frame
+func StepsThisFrame() int {
+	if playback == nil {
+		return 0
+	}
+	if seekTarget >= 0 {
+		steps := min(seekTarget-cursor, seekBudget)
+		if steps <= 0 {
+			seekTarget = -1
+			return 0
+		}
+		return steps
+	}
+	if paused {
+		return 0
+	}
+	return speed
+}
+
+func formatTicks(ticks int) string {
+	seconds := ticks / 60
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+}
+
+// DrawPlaybackHUD shows the replay position, speed and desync warnings
+func DrawPlaybackHUD() {
+	if playback == nil {
+		return
+	}
+
+	screenWidth := int32(600)
+	barX, barY, barWidth := int32(150), int32(90), int32(300)
+	progress := float32(cursor) / float32(max(1, len(playback.Inputs)))
+	rl.DrawRectangle(barX, barY, barWidth, 6, rl.NewColor(20, 20, 40, 200))
+	rl.DrawRectangle(barX, barY, int32(float32(barWidth)*progress), 6, rl.NewColor(255, 90, 90, 255))
+
+	status := fmt.Sprintf("REPLAY %dx  %s / %s", speed, formatTicks(cursor), formatTicks(len(playback.Inputs)))
+	switch {
+	case IsSeeking():
+		status += "  SEEKING"
+	case paused:
+		status += "  PAUSED"
+	case Finished():
+		status += "  END"
+	}
+	rl.DrawText(status, screenWidth/2-rl.MeasureText(status, 16)/2, barY+10, 16, rl.White)
+
+	hint := "Space: Pause  1/2/3: Speed  Left/Right: Seek  Esc: Stop"
+	rl.DrawText(hint, screenWidth/2-rl.MeasureText(hint, 14)/2, barY+30, 14, rl.LightGray)
+
+	y := barY + 50
+	if BuildMismatch() {
+		warning := "Recorded with a different build"
+		rl.DrawText(warning, screenWidth/2-rl.MeasureText(warning, 14)/2, y, 14, rl.Orange)
+		y += 18
+	}
+	if tick, ok := Desynced(); ok {
+		warning := fmt.Sprintf("DESYNC at %s (tick %d)", formatTicks(tick), tick)
+		rl.DrawText(warning, screenWidth/2-rl.MeasureText(warning, 14)/2, y, 14, rl.Red)
+	}
+}
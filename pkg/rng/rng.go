@@ -0,0 +1,48 @@
+package rng
+
+import "math/rand/v2"
+
+// Gameplay randomness goes through here so a run plays out the same from the same seed.
+// Cosmetic randomness (particles, sound variation) can keep using math/rand.
+
+var (
+	seed int64
+	pcg  = rand.NewPCG(0, 0)
+	src  = rand.New(pcg)
+)
+
+// Seed restarts the generator, every run calls this once before anything spawns
+func Seed(s int64) {
+	seed = s
+	pcg.Seed(uint64(s), uint64(s)^0x9e3779b97f4a7c15)
+}
+
+func CurrentSeed() int64 {
+	return seed
+}
+
+func Intn(n int) int {
+	return src.IntN(n)
+}
+
+func Float32() float32 {
+	return src.Float32()
+}
+
+func Float64() float64 {
+	return src.Float64()
+}
+
+func Shuffle(n int, swap func(i, j int)) {
+	src.Shuffle(n, swap)
+}
+
+// State and Restore capture the exact generator position, e.g. for save games
+func State() ([]byte, error) {
+	return pcg.MarshalBinary()
+}
+
+func Restore(s int64, state []byte) error {
+	seed = s
+	return pcg.UnmarshalBinary(state)
+}
@@ -0,0 +1,148 @@
+package savegame
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"axelot/pkg/pickup"
+	"axelot/pkg/player"
+	"axelot/pkg/slime"
+	"axelot/pkg/storage"
+	"axelot/pkg/waves"
+)
+
+// Version of the file format, bump it and add a migration when the layout changes
+const Version = 2
+
+var ErrNewer = errors.New("save was written by a newer version of the game")
+
+// File is a run in progress, written by Save & Quit and deleted once continued
+type File struct {
+	Version int       `json:"version"`
+	Date    time.Time `json:"date"`
+	Build   string    `json:"build"` // informational, saves load across builds
+
+	Seed    int64          `json:"seed"`
+	RNG     []byte         `json:"rng"` // generator state, restored last
+	Loadout map[string]int `json:"loadout"`
+
+	SurvivalTime int `json:"survivalTime"`
+	MaxCombo     int `json:"maxCombo"`
+
+	Player   player.Snapshot `json:"player"`
+	Upgrades map[string]int  `json:"upgrades"`
+	Slimes   slime.Snapshot  `json:"slimes"`
+	Waves    waves.Snapshot  `json:"waves"`
+	Pickups  []pickup.Pickup `json:"pickups"`
+}
+
+// migrations[v] turns a version v file into version v+1, working on the raw JSON
+var migrations = map[int]func(raw map[string]json.RawMessage) error{
+	1: migratePlayerDir,
+}
+
+// Version 1 saved the sprite sheet row as player.dir, 4 and 5 were the attack and hurt rows.
+// Version 2 only stores facings (up, down, left, right), taken from the facing vector for those rows.
+func migratePlayerDir(raw map[string]json.RawMessage) error {
+	var p map[string]json.RawMessage
+	if err := json.Unmarshal(raw["player"], &p); err != nil {
+		return fmt.Errorf("player: %w", err)
+	}
+	var dir int
+	if err := json.Unmarshal(p["dir"], &dir); err != nil || (dir != 4 && dir != 5) {
+		return nil
+	}
+
+	var fx, fy float32
+	json.Unmarshal(p["facingX"], &fx)
+	json.Unmarshal(p["facingY"], &fy)
+	switch {
+	case fx == 0 && fy == 0:
+		dir = 1
+	case fx*fx > fy*fy && fx < 0:
+		dir = 2
+	case fx*fx > fy*fy:
+		dir = 3
+	case fy < 0:
+		dir = 0
+	default:
+		dir = 1
+	}
+
+	p["dir"] = json.RawMessage(fmt.Sprint(dir))
+	byteValue, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	raw["player"] = byteValue
+	return nil
+}
+
+func Path() string {
+	return storage.Path("savegame.json")
+}
+
+func Exists() bool {
+	_, err := os.Stat(Path())
+	return err == nil
+}
+
+func Delete() {
+	if err := os.Remove(Path()); err != nil && !errors.Is(err, os.ErrNotExist) {
+		fmt.Println("savegame:", err)
+	}
+}
+
+func Save(f *File) error {
+	f.Version = Version
+	f.Date = time.Now()
+	byteValue, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	return storage.WriteFileAtomic(Path(), byteValue)
+}
+
+// Load reads the save, migrating older versions step by step
+func Load() (*File, error) {
+	byteValue, err := os.ReadFile(Path())
+	if err != nil {
+		return nil, err
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(byteValue, &raw); err != nil {
+		storage.Quarantine(Path())
+		return nil, fmt.Errorf("%s: %w", Path(), err)
+	}
+	var version int
+	if err := json.Unmarshal(raw["version"], &version); err != nil || version < 1 {
+		return nil, fmt.Errorf("%s: missing or invalid version", Path())
+	}
+	if version > Version {
+		return nil, fmt.Errorf("%w (version %d, this build reads up to %d)", ErrNewer, version, Version)
+	}
+
+	for ; version < Version; version++ {
+		migrate, ok := migrations[version]
+		if !ok {
+			return nil, fmt.Errorf("save version %d is too old, no migration to version %d", version, version+1)
+		}
+		if err := migrate(raw); err != nil {
+			return nil, fmt.Errorf("migrating save from version %d: %w", version, err)
+		}
+	}
+	raw["version"] = json.RawMessage(fmt.Sprint(Version))
+
+	if byteValue, err = json.Marshal(raw); err != nil {
+		return nil, err
+	}
+	f := &File{}
+	if err := json.Unmarshal(byteValue, f); err != nil {
+		return nil, fmt.Errorf("%s: %w", Path(), err)
+	}
+	return f, nil
+}
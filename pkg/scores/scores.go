@@ -0,0 +1,211 @@
+package scores
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"axelot/pkg/storage"
+)
+
+const version = 1
+
+// Entry is one finished run
+type Entry struct {
+	Name     string    `json:"name"`
+	Date     time.Time `json:"date"`
+	Seed     int64     `json:"seed"`
+	Kills    int       `json:"kills"`
+	Time     int       `json:"time"` // frames survived
+	MaxCombo int       `json:"maxCombo"`
+	Level    int       `json:"level"`
+	Victory  bool      `json:"victory"`
+}
+
+// SortKey picks which stat a leaderboard view ranks by
+type SortKey int
+
+const (
+	ByKills SortKey = iota
+	ByTime
+	ByCombo
+)
+
+var SortKeys = []SortKey{ByKills, ByTime, ByCombo}
+
+func (k SortKey) String() string {
+	switch k {
+	case ByTime:
+		return "Time"
+	case ByCombo:
+		return "Combo"
+	}
+	return "Kills"
+}
+
+func (k SortKey) value(e *Entry) int {
+	switch k {
+	case ByTime:
+		return e.Time
+	case ByCombo:
+		return e.MaxCombo
+	}
+	return e.Kills
+}
+
+type scoreFile struct {
+	Version  int     `json:"version"`
+	LastName string  `json:"lastName"`
+	Board    []Entry `json:"board"`   // union of the top runs of every view
+	History  []Entry `json:"history"` // newest first
+}
+
+var (
+	TableSize   = 10 // runs shown per leaderboard view
+	HistorySize = 50
+
+	data     = scoreFile{Version: version}
+	savePath = storage.Path("scores.json")
+)
+
+func InitScores() {
+	if err := Load(); err != nil {
+		fmt.Println("scores:", err, "- starting a new table")
+	}
+}
+
+func Load() error {
+	data = scoreFile{Version: version}
+
+	byteValue, err := os.ReadFile(savePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var loaded scoreFile
+	if err := json.Unmarshal(byteValue, &loaded); err != nil {
+		storage.Quarantine(savePath)
+		return fmt.Errorf("%s: %w", savePath, err)
+	}
+	data = loaded
+	return nil
+}
+
+func Save() error {
+	data.Version = version
+	byteValue, err := json.MarshalIndent(&data, "", "  ")
+	if err != nil {
+		return err
+	}
+	return storage.WriteFileAtomic(savePath, byteValue)
+}
+
+// sorted returns the entries ordered by key, ties go to the earlier run
+func sorted(entries []Entry, key SortKey) []Entry {
+	out := append([]Entry(nil), entries...)
+	sort.SliceStable(out, func(i, j int) bool {
+		a, b := key.value(&out[i]), key.value(&out[j])
+		if a != b {
+			return a > b
+		}
+		return out[i].Date.Before(out[j].Date)
+	})
+	return out
+}
+
+// Top is the leaderboard view for key
+func Top(key SortKey) []Entry {
+	top := sorted(data.Board, key)
+	if len(top) > TableSize {
+		top = top[:TableSize]
+	}
+	return top
+}
+
+func History() []Entry {
+	return data.History
+}
+
+func LastName() string {
+	return data.LastName
+}
+
+// Qualifies is whether the run would make it onto any leaderboard view
+func Qualifies(e Entry) bool {
+	for _, key := range SortKeys {
+		top := Top(key)
+		if key.value(&e) <= 0 {
+			continue
+		}
+		if len(top) < TableSize || key.value(&e) > key.value(&top[len(top)-1]) {
+			return true
+		}
+	}
+	return false
+}
+
+// RecordRun adds a finished run to the history
+func RecordRun(e Entry) {
+	data.History = append([]Entry{e}, data.History...)
+	if len(data.History) > HistorySize {
+		data.History = data.History[:HistorySize]
+	}
+	if err := Save(); err != nil {
+		fmt.Println("scores:", err)
+	}
+}
+
+// SubmitHighScore puts a named run on the leaderboard and names it in the history too
+func SubmitHighScore(e Entry, name string) {
+	e.Name = name
+	data.LastName = name
+	for i := range data.History {
+		if data.History[i].Date.Equal(e.Date) && data.History[i].Seed == e.Seed {
+			data.History[i].Name = name
+		}
+	}
+
+	data.Board = append(data.Board, e)
+	prune()
+	if err := Save(); err != nil {
+		fmt.Println("scores:", err)
+	}
+}
+
+// prune drops runs that aren't in the top of any view
+func prune() {
+	keep := map[int]bool{}
+	for _, key := range SortKeys {
+		order := make([]int, len(data.Board))
+		for i := range order {
+			order[i] = i
+		}
+		sort.SliceStable(order, func(i, j int) bool {
+			a, b := key.value(&data.Board[order[i]]), key.value(&data.Board[order[j]])
+			if a != b {
+				return a > b
+			}
+			return data.Board[order[i]].Date.Before(data.Board[order[j]].Date)
+		})
+		for rank, index := range order {
+			if rank >= TableSize {
+				break
+			}
+			keep[index] = true
+		}
+	}
+
+	board := data.Board[:0]
+	for i, e := range data.Board {
+		if keep[i] {
+			board = append(board, e)
+		}
+	}
+	data.Board = board
+}
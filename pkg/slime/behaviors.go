@@ -0,0 +1,398 @@
+package slime
+
+import (
+	"axelot/pkg/ai"
+	"axelot/pkg/projectile"
+	"axelot/pkg/rng"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// AIParams are the tuning numbers of a behavior, 0 keeps the default.
+// The chances are pointers so an explicit 0 in enemies.json turns the behavior off instead.
+type AIParams struct {
+	ChaseRange     float32  `json:"chaseRange,omitempty"`     // only closes in below this distance
+	MinDistance    float32  `json:"minDistance,omitempty"`    // stops pushing into the player
+	LeashFactor    float32  `json:"leashFactor,omitempty"`    // gives up beyond aggroRange times this
+	RetreatRange   float32  `json:"retreatRange,omitempty"`   // backs off until this far away
+	RetreatSpeed   float32  `json:"retreatSpeed,omitempty"`   // pixels per frame
+	RetreatTime    int      `json:"retreatTime,omitempty"`    // frames before returning to the fight
+	RechaseChance  *float32 `json:"rechaseChance,omitempty"`  // chance to keep fighting after an attack
+	PauseChance    *float32 `json:"pauseChance,omitempty"`    // per frame chance to stop while wandering
+	HesitateChance *float32 `json:"hesitateChance,omitempty"` // per frame chance to stop while chasing
+}
+
+// The original jellyfish numbers
+var defaultAIParams = AIParams{
+	ChaseRange:     150,
+	MinDistance:    8,
+	LeashFactor:    1.5,
+	RetreatRange:   60,
+	RetreatSpeed:   1,
+	RetreatTime:    90,
+	RechaseChance:  chance(0.9),
+	PauseChance:    chance(0.08),
+	HesitateChance: chance(0.1),
+}
+
+func chance(v float32) *float32 {
+	return &v
+}
+
+func (p *AIParams) fillDefaults() {
+	d := defaultAIParams
+	if p.ChaseRange == 0 {
+		p.ChaseRange = d.ChaseRange
+	}
+	if p.MinDistance == 0 {
+		p.MinDistance = d.MinDistance
+	}
+	if p.LeashFactor == 0 {
+		p.LeashFactor = d.LeashFactor
+	}
+	if p.RetreatRange == 0 {
+		p.RetreatRange = d.RetreatRange
+	}
+	if p.RetreatSpeed == 0 {
+		p.RetreatSpeed = d.RetreatSpeed
+	}
+	if p.RetreatTime == 0 {
+		p.RetreatTime = d.RetreatTime
+	}
+	if p.RechaseChance == nil {
+		p.RechaseChance = chance(*d.RechaseChance)
+	}
+	if p.PauseChance == nil {
+		p.PauseChance = chance(*d.PauseChance)
+	}
+	if p.HesitateChance == nil {
+		p.HesitateChance = chance(*d.HesitateChance)
+	}
+}
+
+// Blackboard keys
+const (
+	bbDist        = "dist"
+	bbPlayerX     = "playerX" // sprite corner, like playerPos
+	bbPlayerY     = "playerY"
+	bbTargetX     = "targetX"
+	bbTargetY     = "targetY"
+	bbWanderTimer = "wanderTimer"
+	bbPaused      = "paused"
+	bbStun        = "stun"
+)
+
+// State names, also shown by the debug overlay
+const (
+	wanderNode  = "wander"
+	chaseNode   = "chase"
+	kiteNode    = "kite"
+	attackNode  = "attack"
+	retreatNode = "retreat"
+	stunnedNode = "stunned"
+)
+
+type behavior = ai.Machine[*Slime]
+
+var (
+	behaviors = map[string]*behavior{
+		"jellyfish": mustBehavior(ai.NewMachine("jellyfish", wanderNode,
+			wanderState(),
+			chaseState(),
+			attackState(
+				ai.Transition[*Slime]{To: chaseNode, When: ai.And(attackDone, rechase)},
+				ai.Transition[*Slime]{To: retreatNode, When: attackDone},
+			),
+			ai.State[*Slime]{
+				Name:   retreatNode,
+				Tag:    int(Retreating),
+				Update: retreatUpdate,
+				Exit: func(s *Slime, ctx *ai.Context) {
+					ctx.Board.Set(bbWanderTimer, float32(rng.Intn(60)+30))
+				},
+				Transitions: []ai.Transition[*Slime]{
+					{To: chaseNode, When: func(s *Slime, ctx *ai.Context) bool { return ctx.Timer > s.Type.AI.RetreatTime }},
+					{To: wanderNode, When: func(s *Slime, ctx *ai.Context) bool { return ctx.Board.Get(bbDist) >= s.Type.AI.RetreatRange }},
+				},
+			},
+			stunnedState(),
+		)),
+
+		// Keeps its distance and shoots, never runs away after an attack
+		"shooter": mustBehavior(ai.NewMachine("shooter", wanderNode,
+			wanderState(),
+			chaseState(ai.Transition[*Slime]{To: kiteNode, When: tooClose}),
+			ai.State[*Slime]{
+				Name:   kiteNode,
+				Tag:    int(Chasing),
+				Update: kiteUpdate,
+				Transitions: []ai.Transition[*Slime]{
+					{To: attackNode, When: canAttack},
+					{To: chaseNode, When: ai.Not(tooClose)},
+				},
+			},
+			attackState(ai.Transition[*Slime]{To: chaseNode, When: attackDone}),
+			stunnedState(),
+		)),
+	}
+
+	// Set for the duration of SlimeMoving
+	attackPlayer func(source rl.Vector2, damage float32)
+)
+
+func mustBehavior(m *behavior, err error) *behavior {
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// defaultBehavior keeps old data files working
+func defaultBehavior(def *EnemyDef) string {
+	if def.Attack.Pattern == RangedAttack {
+		return "shooter"
+	}
+	return "jellyfish"
+}
+
+// Conditions
+
+func canAttack(s *Slime, ctx *ai.Context) bool {
+	return ctx.Board.Get(bbDist) <= s.Type.Attack.Range && globalFrameCount-s.LastAttack >= s.Type.Attack.Cooldown
+}
+
+func attackDone(s *Slime, ctx *ai.Context) bool {
+	return s.AttackTimer <= 0
+}
+
+func rechase(s *Slime, ctx *ai.Context) bool {
+	return rng.Float32() < *s.Type.AI.RechaseChance
+}
+
+func tooClose(s *Slime, ctx *ai.Context) bool {
+	return ctx.Board.Get(bbDist) < s.Type.Attack.KeepDistance
+}
+
+// States shared by the behaviors
+
+func wanderState() ai.State[*Slime] {
+	return ai.State[*Slime]{
+		Name:   wanderNode,
+		Tag:    int(Wandering),
+		Update: wanderUpdate,
+		Transitions: []ai.Transition[*Slime]{
+			{To: chaseNode, When: func(s *Slime, ctx *ai.Context) bool {
+				return ctx.Board.Get(bbPaused) == 0 && ctx.Board.Get(bbDist) < s.aggroRange
+			}},
+		},
+	}
+}
+
+func chaseState(extra ...ai.Transition[*Slime]) ai.State[*Slime] {
+	transitions := []ai.Transition[*Slime]{{To: attackNode, When: canAttack}}
+	transitions = append(transitions, extra...)
+	transitions = append(transitions, ai.Transition[*Slime]{To: wanderNode, When: func(s *Slime, ctx *ai.Context) bool {
+		return ctx.Board.Get(bbDist) > s.aggroRange*s.Type.AI.LeashFactor
+	}})
+
+	return ai.State[*Slime]{
+		Name:   chaseNode,
+		Tag:    int(Chasing),
+		Update: chaseUpdate,
+		Exit: func(s *Slime, ctx *ai.Context) {
+			ctx.Board.Set(bbWanderTimer, 30)
+		},
+		Transitions: transitions,
+	}
+}
+
+func attackState(after ...ai.Transition[*Slime]) ai.State[*Slime] {
+	return ai.State[*Slime]{
+		Name:        attackNode,
+		Tag:         int(Attacking),
+		Enter:       attackEnter,
+		Update:      attackUpdate,
+		Exit:        func(s *Slime, ctx *ai.Context) { s.IsAttacking = false },
+		Transitions: after,
+	}
+}
+
+func stunnedState() ai.State[*Slime] {
+	return ai.State[*Slime]{
+		Name:  stunnedNode,
+		Tag:   int(Stunned),
+		Enter: func(s *Slime, ctx *ai.Context) { s.IsAttacking = false },
+		Update: func(s *Slime, ctx *ai.Context) {
+			ctx.Board.Tick(bbStun)
+		},
+		Transitions: []ai.Transition[*Slime]{
+			{To: chaseNode, When: func(s *Slime, ctx *ai.Context) bool { return ctx.Board.Get(bbStun) <= 0 }},
+		},
+	}
+}
+
+// Actions
+
+func wanderUpdate(s *Slime, ctx *ai.Context) {
+	b := ctx.Board
+	if b.Tick(bbWanderTimer) {
+		radius := rng.Float32() * s.patrolRadius
+		b.Set(bbTargetX, s.Dest.X+radius*float32(rng.Intn(3)-1))
+		b.Set(bbTargetY, s.Dest.Y+radius*float32(rng.Intn(3)-1))
+		b.Set(bbWanderTimer, float32(rng.Intn(180)+120))
+	}
+
+	// Sometimes just pause and look around
+	b.Set(bbPaused, 0)
+	if rng.Float32() < *s.Type.AI.PauseChance {
+		b.Set(bbPaused, 1)
+		return
+	}
+
+	dirX := b.Get(bbTargetX) - s.Dest.X
+	dirY := b.Get(bbTargetY) - s.Dest.Y
+	length := rl.Vector2Length(rl.NewVector2(dirX, dirY))
+	if length > 2 {
+		// Lazy movement with random hesitation
+		s.intent.seek = rl.NewVector2(dirX/length, dirY/length)
+		s.intent.speed = s.Type.Speed * (0.25 + rng.Float32()*0.4)
+	}
+}
+
+func chaseUpdate(s *Slime, ctx *ai.Context) {
+	b := ctx.Board
+	dist := b.Get(bbDist)
+	if canAttack(s, ctx) || dist >= s.Type.AI.ChaseRange || dist <= s.Type.AI.MinDistance {
+		return
+	}
+
+	// Head for a spot on a ring around the player so a group surrounds instead of stacking
+	playerCenter := rl.NewVector2(b.Get(bbPlayerX)+16, b.Get(bbPlayerY)+16)
+	target := surroundPoint(s, playerCenter)
+	feet := s.feet()
+	target = rl.Vector2Add(target, rl.Vector2Subtract(feet, s.Center()))
+	if rl.Vector2Distance(feet, target) < 3 {
+		return // in position, separation still spreads the group
+	}
+
+	// Follow a path around islands, straight at the target when it can't be reached
+	dir, ok := s.path.Direction(feet, target, s.HitBox.Width/2, s.Type.Movement)
+	if !ok {
+		dir = rl.Vector2Normalize(rl.Vector2Subtract(target, feet))
+	}
+
+	// Natural movement - sometimes hesitate or overshoot
+	hesitation := rng.Float32()
+	if hesitation < *s.Type.AI.HesitateChance {
+		return // pause like real animals
+	} else if hesitation < *s.Type.AI.HesitateChance*2 {
+		dir.X += (rng.Float32() - 0.5) * 0.3
+		dir.Y += (rng.Float32() - 0.5) * 0.3
+	}
+
+	// Speed based on health (hurt = more desperate)
+	urgency := (s.MaxHealth - s.Health) / s.MaxHealth
+	baseSpeed := s.Type.Speed * (1 + urgency*0.67)
+
+	s.intent.seek = rl.Vector2Normalize(dir)
+	s.intent.speed = baseSpeed + (rng.Float32()-0.5)*0.2
+}
+
+// kiteUpdate backs off to keep the player at range
+func kiteUpdate(s *Slime, ctx *ai.Context) {
+	away := rl.NewVector2(s.Dest.X-ctx.Board.Get(bbPlayerX), s.Dest.Y-ctx.Board.Get(bbPlayerY))
+	if rl.Vector2Length(away) > 0 {
+		s.intent.flee = rl.Vector2Normalize(away)
+		s.intent.speed = s.Type.Speed
+	}
+}
+
+func retreatUpdate(s *Slime, ctx *ai.Context) {
+	if ctx.Board.Get(bbDist) >= s.Type.AI.RetreatRange {
+		return
+	}
+	away := rl.NewVector2(s.Dest.X-ctx.Board.Get(bbPlayerX), s.Dest.Y-ctx.Board.Get(bbPlayerY))
+	if rl.Vector2Length(away) > 0 {
+		s.intent.flee = rl.Vector2Normalize(away)
+		s.intent.speed = s.Type.AI.RetreatSpeed
+	}
+}
+
+func attackEnter(s *Slime, ctx *ai.Context) {
+	atk := &s.Type.Attack
+	s.LastAttack = globalFrameCount
+	s.IsAttacking = true
+	s.AttackTimer = atk.Duration
+	s.attackLanded = false
+	s.hitFrame = false
+
+	// Lunges commit to the direction at the start
+	dist := ctx.Board.Get(bbDist)
+	s.attackAnim = s.Type.pickAttackClip(dist)
+	s.animator.Restart(s.attackAnim)
+	if dist > 0 {
+		s.lungeX = (ctx.Board.Get(bbPlayerX) - s.Dest.X) / dist
+		s.lungeY = (ctx.Board.Get(bbPlayerY) - s.Dest.Y) / dist
+	}
+}
+
+func attackUpdate(s *Slime, ctx *ai.Context) {
+	atk := &s.Type.Attack
+	dist := ctx.Board.Get(bbDist)
+	s.AttackTimer--
+
+	// One hit per attack
+	switch atk.Pattern {
+	case MeleeAttack:
+		// Only if the player is still in reach
+		if !s.attackLanded && s.strikeReady(s.AttackTimer <= atk.Duration-3) && dist <= atk.Range*1.2 {
+			attackPlayer(s.Center(), atk.Damage)
+			s.attackLanded = true
+		}
+	case LungeAttack:
+		// Jumps fly until the clip's hit frame lands them and only hurt on landing, dashes hurt all the way
+		jumping := s.attackAnim == jumpClip
+		landed := jumping && s.strikeReady(s.AttackTimer <= atk.Duration/2)
+		if !landed {
+			s.Dest.X += s.lungeX * atk.LungeSpeed
+			s.Dest.Y += s.lungeY * atk.LungeSpeed
+		}
+		reach := 18 * s.Type.Scale
+		if jumping {
+			reach *= jumpLandingScale
+		}
+		if !s.attackLanded && (landed || !jumping) && dist <= reach {
+			attackPlayer(s.Center(), atk.Damage)
+			s.attackLanded = true
+		}
+	case RangedAttack:
+		if !s.attackLanded && s.strikeReady(s.AttackTimer <= atk.Duration/2) {
+			target := rl.NewVector2(ctx.Board.Get(bbPlayerX)+16, ctx.Board.Get(bbPlayerY)+16) // playerPos is the sprite corner
+			projectile.Fire(atk.Projectile, projectile.EnemyFaction, s.Center(), rl.Vector2Subtract(target, s.Center()), atk.Damage)
+			s.attackLanded = true
+		}
+	}
+}
+
+// strikeReady is true from the attack clip's hit frame on, clips without one use the timer window
+func (s *Slime) strikeReady(timerWindow bool) bool {
+	if !s.Type.clips.HasEvent(s.attackAnim, hitEvent) {
+		return timerWindow
+	}
+	if s.animator.Fired(hitEvent) {
+		s.hitFrame = true
+	}
+	return s.hitFrame
+}
+
+// stunBrain interrupts whatever the slime was doing, repeated hits extend the stun
+func stunBrain(s *Slime, frames int) {
+	b := s.brain.Board()
+	if float32(frames) > b.Get(bbStun) {
+		b.Set(bbStun, float32(frames))
+	}
+	if s.brain.Current() != stunnedNode {
+		s.Type.behavior.Set(s, &s.brain, stunnedNode)
+	}
+	s.aiState = Stunned
+}
@@ -0,0 +1,425 @@
+package slime
+
+import (
+	"axelot/pkg/audio"
+	"axelot/pkg/fx"
+	"axelot/pkg/nav"
+	"axelot/pkg/projectile"
+	"axelot/pkg/rng"
+	"fmt"
+	"math"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+type BossAttackKind string
+
+const (
+	RingAttack   BossAttackKind = "ring"   // burst of spines in every direction
+	ChargeAttack BossAttackKind = "charge" // dash across the arena
+	SummonAttack BossAttackKind = "summon" // call in minions
+)
+
+type BossAttackDef struct {
+	Kind      BossAttackKind `json:"kind"`
+	Telegraph int            `json:"telegraph"` // warning frames before the attack fires
+	Cooldown  int            `json:"cooldown"`  // frames after the attack before the next telegraph
+	Damage    float32        `json:"damage"`
+	Count     int            `json:"count"`              // spines or minions
+	Speed     float32        `json:"speed"`              // spine or charge speed
+	Duration  int            `json:"duration,omitempty"` // charge length in frames
+	Minion    string         `json:"minion,omitempty"`   // enemy type to summon
+
+	Projectile *projectile.Def `json:"projectile,omitempty"` // ring shots, spines at Speed when left out
+}
+
+// BossPhase starts once health drops to Threshold (fraction of max health)
+type BossPhase struct {
+	Threshold float32         `json:"threshold"`
+	Speed     float32         `json:"speed"` // multiplier on the enemy speed
+	Attacks   []BossAttackDef `json:"attacks"`
+}
+
+type BossDef struct {
+	Title  string      `json:"title"` // shown during the intro
+	Phases []BossPhase `json:"phases"`
+}
+
+type bossStep int
+
+const (
+	bossIdle bossStep = iota
+	bossTelegraph
+	bossCharging
+	bossPhaseShift
+)
+
+var (
+	bossIntroDuration int = 210 // pan in, hold, pan back
+	bossIntroPan      int = 60
+	bossPhasePause    int = 60 // pause between phases
+
+	boss struct {
+		id         EntityID
+		active     bool
+		defeated   bool
+		introTimer int
+
+		phase      int
+		attack     int // next attack in the phase list
+		step       bossStep
+		timer      int
+		chargeDir  rl.Vector2
+		chargeHit  bool
+		summonAt   []rl.Vector2
+		contactHit int // frames until body contact can hurt again
+	}
+)
+
+func validateBoss(def *EnemyDef) error {
+	b := def.Boss
+	if len(b.Phases) == 0 {
+		return fmt.Errorf("%s: boss without phases", def.ID)
+	}
+	for i, phase := range b.Phases {
+		if len(phase.Attacks) == 0 {
+			return fmt.Errorf("%s: boss phase %d has no attacks", def.ID, i+1)
+		}
+		if i > 0 && phase.Threshold >= b.Phases[i-1].Threshold {
+			return fmt.Errorf("%s: boss phase thresholds must go down", def.ID)
+		}
+		if phase.Speed <= 0 {
+			b.Phases[i].Speed = 1
+		}
+		for j := range phase.Attacks {
+			atk := &b.Phases[i].Attacks[j]
+			switch atk.Kind {
+			case RingAttack:
+				if atk.Projectile == nil {
+					shot := projectile.Spine
+					shot.Speed = atk.Speed
+					atk.Projectile = &shot
+				}
+				if err := atk.Projectile.Validate(); err != nil {
+					return fmt.Errorf("%s: %w", def.ID, err)
+				}
+			case ChargeAttack, SummonAttack:
+			default:
+				return fmt.Errorf("%s: unknown boss attack %q", def.ID, atk.Kind)
+			}
+		}
+	}
+	return nil
+}
+
+// startBoss is called when a boss type spawns, only one boss fight runs at a time
+func startBoss(id EntityID) {
+	boss.id = id
+	boss.active = true
+	boss.defeated = false
+	boss.introTimer = bossIntroDuration
+	boss.phase = 0
+	boss.attack = 0
+	boss.step = bossIdle
+	boss.timer = 60
+	boss.summonAt = boss.summonAt[:0]
+	audio.Play(audio.SlimeDeath)
+}
+
+func resetBoss() {
+	boss.active = false
+	boss.defeated = false
+	boss.introTimer = 0
+}
+
+func IsBossActive() bool {
+	return boss.active
+}
+
+// IsBossDefeated is true once the boss has finished dying
+func IsBossDefeated() bool {
+	return boss.defeated
+}
+
+func IsBossIntroPlaying() bool {
+	return boss.active && boss.introTimer > 0
+}
+
+// UpdateBossIntro runs instead of the normal update while the camera shows the boss
+func UpdateBossIntro() {
+	if boss.introTimer > 0 {
+		boss.introTimer--
+	}
+}
+
+// BossCameraTarget pans from the player camera target to the boss and back during the intro
+func BossCameraTarget(from rl.Vector2) (rl.Vector2, bool) {
+	if !IsBossIntroPlaying() {
+		return from, false
+	}
+	index := findSlime(boss.id)
+	if index < 0 {
+		return from, false
+	}
+
+	// Camera targets are offset like the player camera
+	center := slimes[index].Center()
+	to := rl.NewVector2(center.X-16, center.Y-16)
+
+	elapsed := bossIntroDuration - boss.introTimer
+	t := float32(1)
+	if elapsed < bossIntroPan {
+		t = float32(elapsed) / float32(bossIntroPan)
+	} else if boss.introTimer < bossIntroPan {
+		t = float32(boss.introTimer) / float32(bossIntroPan)
+	}
+	t = t * t * (3 - 2*t) // smoothstep
+	return rl.Vector2Lerp(from, to, t), true
+}
+
+// updateBossState notices when the boss has finished its death animation
+func updateBossState() {
+	if !boss.active {
+		return
+	}
+	index := findSlime(boss.id)
+	if index < 0 || slimes[index].State == Despawned {
+		boss.active = false
+		boss.defeated = true
+	}
+}
+
+func currentBossPhase(slime *Slime) *BossPhase {
+	return &slime.Type.Boss.Phases[boss.phase]
+}
+
+func updateBossAI(slimeIndex int, playerPos rl.Vector2, attackPlayerFunc func(source rl.Vector2, damage float32)) {
+	slime := &slimes[slimeIndex]
+	def := slime.Type.Boss
+	center := slime.Center()
+	playerCenter := rl.NewVector2(playerPos.X+16, playerPos.Y+16)
+
+	// Drop into the next phase when health crosses its threshold
+	healthFraction := slime.Health / slime.MaxHealth
+	if boss.phase+1 < len(def.Phases) && healthFraction <= def.Phases[boss.phase+1].Threshold {
+		boss.phase++
+		boss.attack = 0
+		boss.step = bossPhaseShift
+		boss.timer = bossPhasePause
+		slime.IsAttacking = false
+		fx.Burst(&fx.WaterBurst, center.X, center.Y)
+		audio.PlayAt(audio.ChargeRelease, center)
+	}
+
+	phase := currentBossPhase(slime)
+	atk := &phase.Attacks[boss.attack%len(phase.Attacks)]
+
+	// Touching the queen hurts
+	if boss.contactHit > 0 {
+		boss.contactHit--
+	}
+	if boss.contactHit == 0 && rl.Vector2Distance(center, playerCenter) < slime.HitBox.Width {
+		attackPlayerFunc(center, slime.Type.Attack.Damage)
+		boss.contactHit = 30
+	}
+
+	slime.aiState = Chasing
+	boss.timer--
+
+	switch boss.step {
+	case bossIdle:
+		// Drift towards the player between attacks
+		if rl.Vector2Distance(center, playerCenter) > slime.Type.Attack.Range {
+			dir, ok := slime.path.Direction(slime.feet(), playerCenter, slime.HitBox.Width/2, slime.Type.Movement)
+			if !ok {
+				dir = rl.Vector2Normalize(rl.Vector2Subtract(playerCenter, center))
+			}
+			slime.intent.seek = dir
+			slime.intent.speed = slime.Type.Speed * phase.Speed
+		}
+		if boss.timer <= 0 {
+			beginBossTelegraph(slime, atk, playerCenter)
+		}
+
+	case bossTelegraph:
+		slime.aiState = Attacking // steering only separates while winding up
+		// Charges keep aiming until the last third of the warning
+		if atk.Kind == ChargeAttack && boss.timer > atk.Telegraph/3 {
+			boss.chargeDir = rl.Vector2Normalize(rl.Vector2Subtract(playerCenter, center))
+		}
+		if boss.timer <= 0 {
+			fireBossAttack(slime, atk, playerCenter)
+		}
+
+	case bossCharging:
+		slime.aiState = Attacking
+		next := rl.Vector2Add(slime.feet(), rl.Vector2Scale(boss.chargeDir, atk.Speed))
+		if !nav.GetGrid().WalkableAt(next, slime.Type.Movement) {
+			// Slammed into an island
+			boss.timer = 0
+			fx.Burst(&fx.DashImpact, center.X, center.Y)
+		} else {
+			slime.Dest.X += boss.chargeDir.X * atk.Speed
+			slime.Dest.Y += boss.chargeDir.Y * atk.Speed
+		}
+		if !boss.chargeHit && rl.Vector2Distance(center, playerCenter) < slime.HitBox.Width*1.5 {
+			attackPlayerFunc(center, atk.Damage)
+			boss.chargeHit = true
+		}
+		if boss.timer <= 0 {
+			finishBossAttack(slime, atk)
+		}
+
+	case bossPhaseShift:
+		slime.aiState = Attacking
+		if boss.timer <= 0 {
+			boss.step = bossIdle
+			boss.timer = 30
+		}
+	}
+}
+
+func beginBossTelegraph(slime *Slime, atk *BossAttackDef, playerCenter rl.Vector2) {
+	boss.step = bossTelegraph
+	boss.timer = atk.Telegraph
+	slime.IsAttacking = true
+	slime.LastAttack = globalFrameCount
+
+	center := slime.Center()
+	switch atk.Kind {
+	case ChargeAttack:
+		boss.chargeDir = rl.Vector2Normalize(rl.Vector2Subtract(playerCenter, center))
+	case SummonAttack:
+		// Pick the spots now so the warning shows where minions will appear
+		boss.summonAt = boss.summonAt[:0]
+		for i := 0; i < atk.Count; i++ {
+			angle := float64(i)*2*math.Pi/float64(atk.Count) + rng.Float64()*0.5
+			point := rl.NewVector2(center.X+float32(math.Cos(angle))*56, center.Y+float32(math.Sin(angle))*56)
+			if nav.GetGrid().WalkableAt(point, nav.Swimmer) {
+				boss.summonAt = append(boss.summonAt, point)
+			}
+		}
+	}
+}
+
+func fireBossAttack(slime *Slime, atk *BossAttackDef, playerCenter rl.Vector2) {
+	center := slime.Center()
+
+	switch atk.Kind {
+	case RingAttack:
+		// Offset every other ring so there is always a gap to slip through
+		offset := float64(boss.attack%2) * math.Pi / float64(atk.Count)
+		for i := 0; i < atk.Count; i++ {
+			angle := float64(i)*2*math.Pi/float64(atk.Count) + offset
+			dir := rl.NewVector2(float32(math.Cos(angle)), float32(math.Sin(angle)))
+			projectile.Fire(atk.Projectile, projectile.EnemyFaction, center, dir, atk.Damage)
+		}
+		fx.Burst(&fx.WaterBurst, center.X, center.Y)
+		audio.PlayAt(audio.ChargeRelease, center)
+		finishBossAttack(slime, atk)
+
+	case ChargeAttack:
+		boss.step = bossCharging
+		boss.timer = atk.Duration
+		boss.chargeHit = false
+		audio.PlayAt(audio.Dash, center)
+
+	case SummonAttack:
+		if def, ok := GetEnemyType(atk.Minion); ok {
+			for _, point := range boss.summonAt {
+				size := def.FrameSize * def.Scale
+				SpawnEnemyAt(def, point.X-size/2, point.Y-size/2)
+				fx.Burst(&fx.JellySplat, point.X, point.Y)
+			}
+		}
+		boss.summonAt = boss.summonAt[:0]
+		finishBossAttack(slime, atk)
+	}
+}
+
+func finishBossAttack(slime *Slime, atk *BossAttackDef) {
+	slime.IsAttacking = false
+	boss.attack++
+	boss.step = bossIdle
+	boss.timer = atk.Cooldown
+}
+
+// DrawBossTelegraph shows where the next attack will land, drawn under the enemies
+func DrawBossTelegraph() {
+	if !boss.active || boss.step != bossTelegraph {
+		return
+	}
+	index := findSlime(boss.id)
+	if index < 0 || !slimes[index].IsAlive() {
+		return
+	}
+
+	slime := &slimes[index]
+	phase := currentBossPhase(slime)
+	atk := &phase.Attacks[boss.attack%len(phase.Attacks)]
+	center := slime.Center()
+
+	// Warnings get brighter and pulse faster as the attack gets closer
+	progress := 1 - float32(boss.timer)/float32(atk.Telegraph)
+	pulse := float32(0.5 + 0.5*math.Sin(float64(globalFrameCount)*(0.2+float64(progress)*0.4)))
+	warn := rl.Fade(rl.NewColor(255, 80, 120, 255), 0.25+0.5*progress*pulse)
+
+	switch atk.Kind {
+	case RingAttack:
+		rl.DrawCircleLinesV(center, slime.Dest.Width/2+progress*24, warn)
+		rl.DrawCircleLinesV(center, slime.Dest.Width/2+progress*24+1, warn)
+	case ChargeAttack:
+		end := rl.Vector2Add(center, rl.Vector2Scale(boss.chargeDir, atk.Speed*float32(atk.Duration)))
+		rl.DrawLineEx(center, end, slime.HitBox.Width, rl.Fade(warn, 0.4))
+		rl.DrawLineEx(center, end, 2, warn)
+	case SummonAttack:
+		for _, point := range boss.summonAt {
+			rl.DrawCircleLinesV(point, 4+progress*8, warn)
+		}
+	}
+}
+
+// DrawBossHUD draws the health bar along the top and the intro title, in screen space
+func DrawBossHUD() {
+	if !boss.active {
+		return
+	}
+	index := findSlime(boss.id)
+	if index < 0 {
+		return
+	}
+	slime := &slimes[index]
+
+	if IsBossIntroPlaying() {
+		elapsed := bossIntroDuration - boss.introTimer
+		if elapsed > bossIntroPan && boss.introTimer > bossIntroPan {
+			title := slime.Type.Boss.Title
+			if title == "" {
+				title = slime.Type.Name
+			}
+			width := rl.MeasureText(title, 32)
+			rl.DrawText(title, int32(screenWidth/2)-width/2+2, 462, 32, rl.Fade(rl.Black, 0.5))
+			rl.DrawText(title, int32(screenWidth/2)-width/2, 460, 32, rl.White)
+		}
+		return
+	}
+
+	barX, barY := int32(100), int32(56)
+	barWidth, barHeight := int32(screenWidth-200), int32(10)
+
+	name := slime.Type.Name
+	rl.DrawText(name, int32(screenWidth/2)-rl.MeasureText(name, 16)/2, 36, 16, rl.White)
+
+	fill := slime.Health / slime.MaxHealth
+	if fill < 0 {
+		fill = 0
+	}
+	rl.DrawRectangle(barX-2, barY-2, barWidth+4, barHeight+4, rl.NewColor(20, 20, 40, 200))
+	rl.DrawRectangle(barX, barY, int32(float32(barWidth)*fill), barHeight, rl.NewColor(230, 90, 170, 255))
+
+	// Tick marks where the next phases start
+	for _, phase := range slime.Type.Boss.Phases[1:] {
+		x := barX + int32(float32(barWidth)*phase.Threshold)
+		rl.DrawRectangle(x, barY-2, 2, barHeight+4, rl.White)
+	}
+}
@@ -0,0 +1,72 @@
+package slime
+
+import (
+	"axelot/pkg/console"
+	"fmt"
+	"strconv"
+)
+
+func registerCommands() {
+	console.IntVar("maxSlimes", "most enemies alive at once", &maxSlimes)
+
+	console.Register(console.Command{
+		Name:  "spawn",
+		Usage: "<enemy> [count]",
+		Help:  "spawn enemies on random water tiles",
+		Cheat: true,
+		Run:   spawnCommand,
+		Complete: func(arg int, prefix string) []string {
+			if arg != 0 {
+				return nil
+			}
+			return GetEnemyTypeIDs()
+		},
+	})
+
+	console.Register(console.Command{
+		Name:  "kill_all",
+		Help:  "kill every enemy, without xp or loot",
+		Cheat: true,
+		Run: func(args []string) error {
+			killed := 0
+			for i := range slimes {
+				if slimes[i].State == Dying {
+					continue
+				}
+				slimes[i].Health = 0
+				slimes[i].State = Dying
+				slimes[i].IsAttacking = false
+				slimes[i].DeathTimer = 0
+				killed++
+			}
+			console.Printf("killed %d", killed)
+			return nil
+		},
+	})
+}
+
+func spawnCommand(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing enemy type")
+	}
+	if _, ok := GetEnemyType(args[0]); !ok {
+		return fmt.Errorf("unknown enemy %q", args[0])
+	}
+	count := 1
+	if len(args) > 1 {
+		n, err := strconv.Atoi(args[1])
+		if err != nil || n <= 0 {
+			return fmt.Errorf("%q is not a count", args[1])
+		}
+		count = n
+	}
+
+	spawned := 0
+	for i := 0; i < count; i++ {
+		if SpawnEnemy(args[0]) {
+			spawned++
+		}
+	}
+	console.Printf("spawned %d %s", spawned, args[0])
+	return nil
+}
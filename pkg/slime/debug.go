@@ -0,0 +1,48 @@
+package slime
+
+import (
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// DrawDebug shows hitboxes, aggro and attack ranges, the AI target and path of every slime
+func DrawDebug() {
+	for i := range slimes {
+		s := &slimes[i]
+		if !s.IsAlive() {
+			continue
+		}
+		center := s.Center()
+
+		rl.DrawRectangleLinesEx(s.HitBox, 1, rl.Red)
+		rl.DrawCircleLinesV(center, s.aggroRange, rl.NewColor(255, 161, 0, 70))
+		if s.Type.Attack.Range > 0 {
+			rl.DrawCircleLinesV(center, s.Type.Attack.Range, rl.NewColor(230, 41, 55, 150))
+		}
+
+		// Where the brain wants to go, and the path it takes there
+		board := s.brain.Board()
+		keyX, keyY := bbTargetX, bbTargetY
+		if s.aiState != Wandering {
+			keyX, keyY = bbPlayerX, bbPlayerY
+		}
+		target := rl.NewVector2(board.Get(keyX)+s.Dest.Width/2, board.Get(keyY)+s.Dest.Height/2)
+		rl.DrawLineV(center, target, rl.NewColor(255, 255, 255, 90))
+		rl.DrawCircleV(target, 2, rl.SkyBlue)
+		points := s.path.Points()
+		for j := 1; j < len(points); j++ {
+			rl.DrawLineV(points[j-1], points[j], rl.SkyBlue)
+		}
+
+		// Active node of the brain and the one before it
+		label := s.brain.Current()
+		if s.Type.Boss != nil {
+			label = "boss"
+		} else if prev := s.brain.Previous(); prev != "" {
+			label += " <" + prev
+		}
+		x := int32(center.X) - rl.MeasureText(label, 8)/2
+		y := int32(s.Dest.Y + s.Dest.Height)
+		rl.DrawText(label, x+1, y+1, 8, rl.Black)
+		rl.DrawText(label, x, y, 8, rl.White)
+	}
+}
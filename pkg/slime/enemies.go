@@ -0,0 +1,374 @@
+package slime
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"axelot/pkg/anim"
+	"axelot/pkg/asset"
+	"axelot/pkg/nav"
+	"axelot/pkg/pickup"
+	"axelot/pkg/projectile"
+	"axelot/pkg/rng"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+type AttackPattern string
+
+const (
+	MeleeAttack  AttackPattern = "melee"  // bite when close
+	LungeAttack  AttackPattern = "lunge"  // dart at the player, hits on contact
+	RangedAttack AttackPattern = "ranged" // keep distance and shoot spines
+)
+
+// Animation is a run of frames on the enemy sprite sheet, or on its own sheet
+type Animation struct {
+	Sprite string       `json:"sprite,omitempty"` // the enemy's sprite when left out
+	Row    int          `json:"row"`
+	Start  int          `json:"start,omitempty"` // frames past the right edge continue on the next row
+	Frames int          `json:"frames"`
+	Speed  int          `json:"speed"`            // game frames per animation frame
+	Events []anim.Event `json:"events,omitempty"` // e.g. the "hit" frame of an attack
+}
+
+type AnimationSet struct {
+	Move   Animation `json:"move"`
+	Attack Animation `json:"attack"`
+	Death  Animation `json:"death"`
+
+	// Lunge variants, a jump when the player is close and a dash from further away
+	Jump *Animation `json:"jump,omitempty"`
+	Dash *Animation `json:"dash,omitempty"`
+}
+
+type AttackDef struct {
+	Pattern      AttackPattern `json:"pattern"`
+	Range        float32       `json:"range"`
+	Cooldown     int           `json:"cooldown"`
+	Duration     int           `json:"duration"`
+	Damage       float32       `json:"damage"`
+	LungeSpeed   float32       `json:"lungeSpeed,omitempty"`
+	SpineSpeed   float32       `json:"spineSpeed,omitempty"`
+	KeepDistance float32       `json:"keepDistance,omitempty"` // ranged enemies back off when closer than this
+
+	// What ranged enemies shoot, spines at SpineSpeed when left out
+	Projectile *projectile.Def `json:"projectile,omitempty"`
+}
+
+type LootDrop struct {
+	Item   string  `json:"item"`
+	Chance float32 `json:"chance"`
+	Min    int     `json:"min"`
+	Max    int     `json:"max"`
+}
+
+// EnemyDef describes an enemy type, loaded from assets/enemies.json
+type EnemyDef struct {
+	ID        string   `json:"id"`
+	Name      string   `json:"name"`
+	Sprite    string   `json:"sprite"` // asset manifest key or file path
+	FrameSize float32  `json:"frameSize"`
+	Scale     float32  `json:"scale"`
+	Tint      [3]uint8 `json:"tint"`
+
+	Animations AnimationSet `json:"animations"`
+
+	Health     float32      `json:"health"`
+	Speed      float32      `json:"speed"`
+	Movement   nav.Mobility `json:"movement"`   // "swim" (default) or "amphibious"
+	AggroRange [2]float32   `json:"aggroRange"` // random per enemy between min and max
+	Attack     AttackDef    `json:"attack"`
+	Loot       []LootDrop   `json:"loot"`
+	XP         int          `json:"xp"` // experience for the kill, from health when left out
+
+	SpawnWeight int `json:"spawnWeight"` // 0 = never picked by random spawning
+
+	Behavior string   `json:"behavior"` // AI state machine, picked from the attack pattern when empty
+	AI       AIParams `json:"ai"`
+
+	Boss *BossDef `json:"boss,omitempty"` // phases and attacks replace the normal AI
+
+	behavior *behavior
+	clips    *anim.Library
+}
+
+type enemyFile struct {
+	Enemies []EnemyDef `json:"enemies"`
+}
+
+// Clip and event names every enemy library has
+const (
+	mainSheet  = "main"
+	moveClip   = "move"
+	attackClip = "attack"
+	jumpClip   = "jump"
+	dashClip   = "dash"
+	deathClip  = "death"
+	hitEvent   = "hit"
+)
+
+var (
+	enemyTypes     = map[string]*EnemyDef{}
+	enemyTypeOrder []string
+	enemyTextures  = map[string]rl.Texture2D{}
+)
+
+// The jellyfish the game started with, used when the data file can't be read
+var defaultEnemy = EnemyDef{
+	ID:        "jellyfish",
+	Name:      "Jellyfish",
+	Sprite:    "enemy.jellyfish",
+	FrameSize: 32,
+	Scale:     1,
+	Tint:      [3]uint8{255, 255, 255},
+	Animations: AnimationSet{
+		Move:   Animation{Row: 2, Frames: 5, Speed: 12},
+		Attack: Animation{Row: 3, Frames: 6, Speed: 12, Events: []anim.Event{{Frame: 1, Name: hitEvent}}},
+		Death:  Animation{Row: 4, Frames: 3, Speed: 12},
+	},
+	Health:     5,
+	Speed:      0.6,
+	Movement:   nav.Swimmer,
+	AggroRange: [2]float32{120, 200},
+	Attack: AttackDef{
+		Pattern:  MeleeAttack,
+		Range:    25,
+		Cooldown: 60,
+		Duration: 20,
+		Damage:   0.7,
+	},
+	SpawnWeight: 1,
+}
+
+func LoadEnemyTypes(file string) error {
+	byteValue, err := asset.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	var data enemyFile
+	if err := json.Unmarshal(byteValue, &data); err != nil {
+		return fmt.Errorf("%s: %w", file, err)
+	}
+
+	for i := range data.Enemies {
+		if err := RegisterEnemyType(data.Enemies[i]); err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+	}
+
+	return nil
+}
+
+// RegisterEnemyType adds or replaces an enemy type, its sprite is loaded on first use
+func RegisterEnemyType(def EnemyDef) error {
+	if def.ID == "" {
+		return fmt.Errorf("enemy without id")
+	}
+	if def.Health <= 0 || def.FrameSize <= 0 {
+		return fmt.Errorf("%s: health and frameSize must be positive", def.ID)
+	}
+	switch def.Attack.Pattern {
+	case MeleeAttack, LungeAttack, RangedAttack:
+	default:
+		return fmt.Errorf("%s: unknown attack pattern %q", def.ID, def.Attack.Pattern)
+	}
+	switch def.Movement {
+	case "":
+		def.Movement = nav.Swimmer
+	case nav.Swimmer, nav.Amphibious:
+	default:
+		return fmt.Errorf("%s: unknown movement %q", def.ID, def.Movement)
+	}
+	if def.Attack.Pattern == RangedAttack {
+		if def.Attack.Projectile == nil {
+			shot := projectile.Spine
+			if def.Attack.SpineSpeed > 0 {
+				shot.Speed = def.Attack.SpineSpeed
+			}
+			def.Attack.Projectile = &shot
+		}
+		if err := def.Attack.Projectile.Validate(); err != nil {
+			return fmt.Errorf("%s: %w", def.ID, err)
+		}
+	}
+	for _, drop := range def.Loot {
+		if !pickup.IsKnown(pickup.Kind(drop.Item)) {
+			return fmt.Errorf("%s: unknown loot item %q", def.ID, drop.Item)
+		}
+	}
+	if def.Behavior == "" {
+		def.Behavior = defaultBehavior(&def)
+	}
+	b, ok := behaviors[def.Behavior]
+	if !ok {
+		return fmt.Errorf("%s: unknown behavior %q", def.ID, def.Behavior)
+	}
+	def.behavior = b
+	def.AI.fillDefaults()
+	if def.Boss != nil {
+		if err := validateBoss(&def); err != nil {
+			return err
+		}
+	}
+	if def.Scale <= 0 {
+		def.Scale = 1
+	}
+	if def.XP <= 0 {
+		def.XP = int(math.Ceil(float64(def.Health)))
+	}
+	if def.AggroRange[1] < def.AggroRange[0] {
+		def.AggroRange[1] = def.AggroRange[0]
+	}
+	clips, err := buildClips(&def)
+	if err != nil {
+		return fmt.Errorf("%s: %w", def.ID, err)
+	}
+	def.clips = clips
+
+	if _, exists := enemyTypes[def.ID]; !exists {
+		enemyTypeOrder = append(enemyTypeOrder, def.ID)
+	}
+	stored := def
+	enemyTypes[def.ID] = &stored
+	return nil
+}
+
+func GetEnemyType(id string) (*EnemyDef, bool) {
+	def, ok := enemyTypes[id]
+	return def, ok
+}
+
+func GetEnemyTypeIDs() []string {
+	return enemyTypeOrder
+}
+
+// randomEnemyType picks a type weighted by SpawnWeight
+func randomEnemyType() *EnemyDef {
+	total := 0
+	for _, id := range enemyTypeOrder {
+		total += enemyTypes[id].SpawnWeight
+	}
+	if total <= 0 {
+		return nil
+	}
+
+	pick := rng.Intn(total)
+	for _, id := range enemyTypeOrder {
+		pick -= enemyTypes[id].SpawnWeight
+		if pick < 0 {
+			return enemyTypes[id]
+		}
+	}
+	return nil
+}
+
+// dropLoot rolls every entry of the loot table once
+func dropLoot(def *EnemyDef, pos rl.Vector2) {
+	for _, drop := range def.Loot {
+		if rng.Float32() >= drop.Chance {
+			continue
+		}
+		n := drop.Min
+		if drop.Max > drop.Min {
+			n += rng.Intn(drop.Max - drop.Min + 1)
+		}
+		for i := 0; i < n; i++ {
+			pickup.Spawn(pickup.Kind(drop.Item), pos, 1)
+		}
+	}
+}
+
+// buildClips turns the animations into clips, attacks loop like before and deaths hold the last frame
+func buildClips(def *EnemyDef) (*anim.Library, error) {
+	lib := anim.NewLibrary()
+	lib.Sheets[mainSheet] = &anim.Sheet{Texture: def.Sprite, FrameWidth: def.FrameSize, FrameHeight: def.FrameSize}
+
+	attacks := map[string]*Animation{attackClip: &def.Animations.Attack}
+	if def.Animations.Jump != nil {
+		attacks[jumpClip] = def.Animations.Jump
+	}
+	if def.Animations.Dash != nil {
+		attacks[dashClip] = def.Animations.Dash
+	}
+	if len(attacks) > 1 && def.Attack.Pattern != LungeAttack {
+		return nil, fmt.Errorf("jump and dash animations need the lunge attack pattern")
+	}
+
+	add := func(name string, a Animation, loop bool) {
+		sheet := mainSheet
+		if a.Sprite != "" && a.Sprite != def.Sprite {
+			// Sheets are named after their file, the main one keeps its name
+			sheet = a.Sprite
+			lib.Sheets[sheet] = &anim.Sheet{Texture: a.Sprite, FrameWidth: def.FrameSize, FrameHeight: def.FrameSize}
+		}
+		fps := float32(0)
+		if a.Speed > 0 {
+			fps = float32(anim.TickRate) / float32(a.Speed)
+		}
+		lib.Clips[name] = &anim.Clip{Sheet: sheet, Row: a.Row, Start: a.Start, Frames: max(a.Frames, 1), FPS: fps, Loop: loop, Events: a.Events}
+	}
+	add(moveClip, def.Animations.Move, true)
+	add(deathClip, def.Animations.Death, false)
+	for name, a := range attacks {
+		add(name, *a, true)
+	}
+	if err := lib.Link(); err != nil {
+		return nil, err
+	}
+
+	// The hit has to land before the attack is over
+	for name, a := range attacks {
+		for _, ev := range a.Events {
+			if ev.Name == hitEvent && ev.Frame*a.Speed >= def.Attack.Duration {
+				return nil, fmt.Errorf("%s hit frame %d comes after the attack ends", name, ev.Frame)
+			}
+		}
+	}
+	return lib, nil
+}
+
+// newAnimator loads the sprites if needed and starts on the move clip
+func (def *EnemyDef) newAnimator() anim.Animator {
+	for name, sheet := range def.clips.Sheets {
+		def.clips.SetTexture(name, loadEnemyTexture(sheet.Texture))
+	}
+	a := anim.NewAnimator(def.clips)
+	a.Play(moveClip)
+	return a
+}
+
+// pickAttackClip chooses how a lunge looks, types without variants use their attack clip
+func (def *EnemyDef) pickAttackClip(dist float32) string {
+	jump, dash := def.Animations.Jump != nil, def.Animations.Dash != nil
+	switch {
+	case jump && (!dash || dist <= def.Attack.Range/2):
+		return jumpClip
+	case dash:
+		return dashClip
+	}
+	return attackClip
+}
+
+// Types sharing a sprite sheet share the texture, each sheet is acquired once
+func loadEnemyTexture(name string) rl.Texture2D {
+	tex, ok := enemyTextures[name]
+	if !ok {
+		tex = asset.Acquire(name)
+		enemyTextures[name] = tex
+	}
+	return tex
+}
+
+func (def *EnemyDef) TintColor() rl.Color {
+	return rl.NewColor(def.Tint[0], def.Tint[1], def.Tint[2], 255)
+}
+
+func unloadEnemyTextures() {
+	for name := range enemyTextures {
+		asset.Release(name)
+		delete(enemyTextures, name)
+	}
+}
@@ -0,0 +1,179 @@
+package slime
+
+import (
+	"axelot/pkg/ai"
+	"axelot/pkg/combat"
+	"fmt"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// SlimeSnapshot is one live slime in a save game
+type SlimeSnapshot struct {
+	ID           EntityID       `json:"id"`
+	Type         string         `json:"type"`
+	State        Lifecycle      `json:"state"`
+	X            float32        `json:"x"`
+	Y            float32        `json:"y"`
+	Health       float32        `json:"health"`
+	MaxHealth    float32        `json:"maxHealth"`
+	SpawnTimer   int            `json:"spawnTimer"`
+	FrameCount   int            `json:"frameCount"`
+	LastAttack   int            `json:"lastAttack"`
+	Brain        ai.RunnerState `json:"brain"`
+	AggroRange   float32        `json:"aggroRange"`
+	PatrolRadius float32        `json:"patrolRadius"`
+	WanderAngle  float32        `json:"wanderAngle"`
+
+	// A slime saved mid-attack or mid-knockback carries on where it was, the stun is on the brain's board
+	IsAttacking  bool           `json:"isAttacking,omitempty"`
+	AttackTimer  int            `json:"attackTimer,omitempty"`
+	AttackAnim   string         `json:"attackAnim,omitempty"`
+	AttackLanded bool           `json:"attackLanded,omitempty"`
+	HitFrame     bool           `json:"hitFrame,omitempty"`
+	LungeX       float32        `json:"lungeX,omitempty"`
+	LungeY       float32        `json:"lungeY,omitempty"`
+	Knockback    combat.Impulse `json:"knockback"`
+}
+
+type BossSnapshot struct {
+	ID         EntityID     `json:"id"`
+	Active     bool         `json:"active"`
+	Defeated   bool         `json:"defeated"`
+	IntroTimer int          `json:"introTimer"`
+	Phase      int          `json:"phase"`
+	Attack     int          `json:"attack"`
+	Step       int          `json:"step"`
+	Timer      int          `json:"timer"`
+	ChargeDir  rl.Vector2   `json:"chargeDir"`
+	ChargeHit  bool         `json:"chargeHit"`
+	SummonAt   []rl.Vector2 `json:"summonAt,omitempty"`
+	ContactHit int          `json:"contactHit"`
+}
+
+type Snapshot struct {
+	Slimes []SlimeSnapshot `json:"slimes"`
+	NextID EntityID        `json:"nextId"`
+	Frame  int             `json:"frame"`
+	Boss   BossSnapshot    `json:"boss"`
+}
+
+// TakeSnapshot copies every slime that is still in the fight, dying ones already paid out
+func TakeSnapshot() Snapshot {
+	snap := Snapshot{NextID: nextEntityID, Frame: globalFrameCount}
+	for i := range slimes {
+		s := &slimes[i]
+		if s.State == Dying {
+			continue
+		}
+		snap.Slimes = append(snap.Slimes, SlimeSnapshot{
+			ID:           s.ID,
+			Type:         s.Type.ID,
+			State:        s.State,
+			X:            s.Dest.X,
+			Y:            s.Dest.Y,
+			Health:       s.Health,
+			MaxHealth:    s.MaxHealth,
+			SpawnTimer:   s.SpawnTimer,
+			FrameCount:   s.FrameCount,
+			LastAttack:   s.LastAttack,
+			Brain:        s.brain.Save(),
+			AggroRange:   s.aggroRange,
+			PatrolRadius: s.patrolRadius,
+			WanderAngle:  s.wanderAngle,
+			IsAttacking:  s.IsAttacking,
+			AttackTimer:  s.AttackTimer,
+			AttackAnim:   s.attackAnim,
+			AttackLanded: s.attackLanded,
+			HitFrame:     s.hitFrame,
+			LungeX:       s.lungeX,
+			LungeY:       s.lungeY,
+			Knockback:    s.knockback,
+		})
+	}
+
+	snap.Boss = BossSnapshot{
+		ID:         boss.id,
+		Active:     boss.active,
+		Defeated:   boss.defeated,
+		IntroTimer: boss.introTimer,
+		Phase:      boss.phase,
+		Attack:     boss.attack,
+		Step:       int(boss.step),
+		Timer:      boss.timer,
+		ChargeDir:  boss.chargeDir,
+		ChargeHit:  boss.chargeHit,
+		SummonAt:   append([]rl.Vector2(nil), boss.summonAt...),
+		ContactHit: boss.contactHit,
+	}
+	return snap
+}
+
+// RestoreSnapshot replaces every slime with the saved ones, without touching the rng
+func RestoreSnapshot(snap Snapshot) error {
+	ResetSlimes()
+
+	for _, saved := range snap.Slimes {
+		def, ok := GetEnemyType(saved.Type)
+		if !ok {
+			return fmt.Errorf("unknown enemy type %q", saved.Type)
+		}
+		if len(slimes) >= maxSlimes {
+			break
+		}
+
+		size := def.FrameSize * def.Scale
+		hitBoxSize := 10 * def.Scale
+		s := Slime{
+			ID:           saved.ID,
+			State:        saved.State,
+			Type:         def,
+			OldX:         saved.X,
+			OldY:         saved.Y,
+			Dest:         rl.NewRectangle(saved.X, saved.Y, size, size),
+			HitBox:       rl.NewRectangle(0, 0, hitBoxSize, hitBoxSize),
+			FrameCount:   saved.FrameCount,
+			LastAttack:   saved.LastAttack,
+			MaxHealth:    saved.MaxHealth,
+			Health:       saved.Health,
+			SpawnTimer:   saved.SpawnTimer,
+			aggroRange:   saved.AggroRange,
+			patrolRadius: saved.PatrolRadius,
+			wanderAngle:  saved.WanderAngle,
+			IsAttacking:  saved.IsAttacking,
+			AttackTimer:  saved.AttackTimer,
+			animator:     def.newAnimator(),
+			attackAnim:   saved.AttackAnim,
+			attackLanded: saved.AttackLanded,
+			hitFrame:     saved.HitFrame,
+			lungeX:       saved.LungeX,
+			lungeY:       saved.LungeY,
+			knockback:    saved.Knockback,
+		}
+		// Saves from before attack clips were stored, or clips the enemy no longer has
+		if _, ok := def.clips.Clips[s.attackAnim]; !ok {
+			s.attackAnim = attackClip
+		}
+		s.brain.Load(saved.Brain)
+		s.aiState = AIState(def.behavior.Tag(&s.brain))
+		s.updateHealthBar()
+		slimes = append(slimes, s)
+	}
+
+	nextEntityID = snap.NextID
+	globalFrameCount = snap.Frame
+
+	boss.id = snap.Boss.ID
+	boss.active = snap.Boss.Active
+	boss.defeated = snap.Boss.Defeated
+	boss.introTimer = snap.Boss.IntroTimer
+	boss.phase = snap.Boss.Phase
+	boss.attack = snap.Boss.Attack
+	boss.step = bossStep(snap.Boss.Step)
+	boss.timer = snap.Boss.Timer
+	boss.chargeDir = snap.Boss.ChargeDir
+	boss.chargeHit = snap.Boss.ChargeHit
+	boss.summonAt = append(boss.summonAt[:0], snap.Boss.SummonAt...)
+	boss.contactHit = snap.Boss.ContactHit
+	return nil
+}
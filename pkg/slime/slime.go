@@ -1,9 +1,16 @@
 package slime
 
 import (
+	"axelot/pkg/ai"
+	"axelot/pkg/anim"
+	"axelot/pkg/asset"
+	"axelot/pkg/audio"
+	"axelot/pkg/combat"
+	"axelot/pkg/fx"
+	"axelot/pkg/nav"
+	"axelot/pkg/rng"
 	"axelot/pkg/world"
-	"math/rand"
-	"time"
+	"fmt"
 
 	rl "github.com/gen2brain/raylib-go/raylib"
 )
@@ -14,12 +21,11 @@ const (
 )
 
 type Slime struct {
-	Sprite       rl.Texture2D
+	ID           EntityID
+	State        Lifecycle
+	Type         *EnemyDef
 	OldX, OldY   float32
-	Src          rl.Rectangle
 	Dest         rl.Rectangle
-	Dir          int
-	Frame        int
 	HitBox       rl.Rectangle
 	FrameCount   int
 	LastAttack   int
@@ -28,40 +34,62 @@ type Slime struct {
 	MaxHealth    float32
 	Health       float32
 	HealthbarDir int
-	IsDead       bool
+	SpawnTimer   int
 	DeathTimer   int
 
 	// Enhanced AI
-	aiState      AIState
-	stateTimer   int
-	targetX      float32
-	targetY      float32
+	aiState      AIState // mirrors the active node, used for steering and the hurt flash
+	brain        ai.Runner
 	aggroRange   float32
 	patrolRadius float32
-	wanderTimer  int
+	path         nav.Path
+	intent       intent
+	vel          rl.Vector2 // movement last frame, for alignment
+	wanderAngle  float32
+
+	animator anim.Animator
+
+	// Hit response
+	knockback    combat.Impulse
+	attackLanded bool
+	hitFrame     bool   // the attack clip reached its hit frame
+	attackAnim   string // attack, jump or dash clip of the current attack
+	lungeX       float32
+	lungeY       float32
 }
 
 var (
-	slimeSprite           rl.Texture2D
 	slimeHealthBarTexture rl.Texture2D
 	slimeHealthBarSrc     rl.Rectangle
 	slimes                []Slime
 
 	slimeHitBoxYOffset   float32 = 3
-	attackCooldown       int     = 60
-	attackRange          float32 = 25
-	attackDuration       int     = 20
 	slimeHealthBarWidth  float32 = 32
 	slimeHealthBarHeight float32 = 8
 	slimeHealthBarOffset float32 = 3
 	deathDuration        int     = 120
+	jumpLandingScale     float32 = 1.5 // landing from a jump hits further than a dash
+	spawnDuration        int     = 20
 
-	spawnTimer    int = 0
-	spawnInterval int = 300 // 500ms at 60 FPS
+	// Dying slimes still count, so the slice never grows past this
+	maxSlimes    int      = 64
+	nextEntityID EntityID = 1
 
 	globalFrameCount int
 )
 
+// EntityID stays the same for the whole life of an enemy, unlike its slice index
+type EntityID uint32
+
+type Lifecycle int
+
+const (
+	Spawning Lifecycle = iota // fading in, can't act or be hit yet
+	Alive
+	Dying     // playing the death animation
+	Despawned // removed at the end of the frame
+)
+
 type AIState int
 
 const (
@@ -72,63 +100,145 @@ const (
 	Stunned
 )
 
+func (s AIState) String() string {
+	switch s {
+	case Chasing:
+		return "chasing"
+	case Attacking:
+		return "attacking"
+	case Retreating:
+		return "retreating"
+	case Stunned:
+		return "stunned"
+	}
+	return "wandering"
+}
+
 func InitSlime() {
-	slimeSprite = rl.LoadTexture("assets/slime/jellyfish_slime.png")
-	slimeHealthBarTexture = rl.LoadTexture("assets/axolotl/Health_Bars_001.png")
-	slimeHealthBarSrc = rl.NewRectangle(0, 0, 128, 32)
+	if err := LoadEnemyTypes("assets/enemies.json"); err != nil || len(enemyTypeOrder) == 0 {
+		fmt.Println("enemies:", err, "- using built-in jellyfish")
+		RegisterEnemyType(defaultEnemy)
+	}
 
-	rand.Seed(time.Now().UnixNano())
+	slimeHealthBarTexture = asset.Acquire("ui.enemy_health")
+	slimeHealthBarSrc = rl.NewRectangle(0, 0, 128, 32)
 
-	SpawnSlime()
+	registerCommands()
 }
 
+// SpawnSlime spawns a random enemy type weighted by spawnWeight
 func SpawnSlime() {
+	def := randomEnemyType()
+	if def == nil {
+		return
+	}
+	SpawnEnemy(def.ID)
+}
+
+// SpawnEnemy spawns a registered enemy type on a random water tile
+func SpawnEnemy(typeID string) bool {
+	def, ok := GetEnemyType(typeID)
+	if !ok || len(slimes) >= maxSlimes {
+		return false
+	}
+
 	waterTiles := world.WaterTiles
 
 	if len(waterTiles) == 0 {
-		return
+		return false
 	}
 
 	maxAttempts := 10
 	for attempt := 0; attempt < maxAttempts; attempt++ {
-		randomIndex := rand.Intn(len(waterTiles))
+		randomIndex := rng.Intn(len(waterTiles))
 		selectedTile := waterTiles[randomIndex]
 
 		x := float32(selectedTile.X * world.WorldMap.TileSize)
 		y := float32(selectedTile.Y * world.WorldMap.TileSize)
 
 		if !IsLocationOnGround(x, y) {
-			newSlime := Slime{
-				Sprite:       slimeSprite,
-				Src:          rl.NewRectangle(0, 0, 32, 32),
-				Dest:         rl.NewRectangle(x, y, 32, 32),
-				Dir:          5,
-				Frame:        0,
-				HitBox:       rl.NewRectangle(0, 0, 10, 10),
-				FrameCount:   0,
-				LastAttack:   0,
-				IsAttacking:  false,
-				AttackTimer:  0,
-				MaxHealth:    5.0,
-				Health:       5.0,
-				HealthbarDir: 0,
-				IsDead:       false,
-				DeathTimer:   0,
-
-				// AI stuff
-				aiState:      Wandering,
-				stateTimer:   0,
-				targetX:      x,
-				targetY:      y,
-				aggroRange:   120.0 + rand.Float32()*80.0, // random aggro range
-				patrolRadius: 50.0 + rand.Float32()*30.0,
-				wanderTimer:  rand.Intn(120) + 60,
-			}
+			return SpawnEnemyAt(def, x, y)
+		}
+	}
+
+	return false
+}
+
+func SpawnEnemyAt(def *EnemyDef, x, y float32) bool {
+	if len(slimes) >= maxSlimes || (def.Boss != nil && boss.active) {
+		return false
+	}
+
+	size := def.FrameSize * def.Scale
+	hitBoxSize := 10 * def.Scale
+
+	newSlime := Slime{
+		ID:           nextEntityID,
+		State:        Spawning,
+		wanderAngle:  rng.Float32() * 2 * rl.Pi,
+		Type:         def,
+		Dest:         rl.NewRectangle(x, y, size, size),
+		HitBox:       rl.NewRectangle(0, 0, hitBoxSize, hitBoxSize),
+		FrameCount:   0,
+		LastAttack:   0,
+		IsAttacking:  false,
+		AttackTimer:  0,
+		MaxHealth:    def.Health,
+		Health:       def.Health,
+		HealthbarDir: 0,
+		SpawnTimer:   0,
+		DeathTimer:   0,
+
+		// AI stuff
+		aiState:      Wandering,
+		aggroRange:   def.AggroRange[0] + rng.Float32()*(def.AggroRange[1]-def.AggroRange[0]), // random aggro range
+		patrolRadius: 50.0 + rng.Float32()*30.0,
+		animator:     def.newAnimator(),
+		attackAnim:   attackClip,
+	}
+	board := newSlime.brain.Board()
+	board.Set(bbTargetX, x)
+	board.Set(bbTargetY, y)
+	board.Set(bbWanderTimer, float32(rng.Intn(120)+60))
+
+	// Bosses are introduced by the camera pan instead of fading in
+	if def.Boss != nil {
+		newSlime.State = Alive
+		newSlime.aggroRange = 10000
+		startBoss(newSlime.ID)
+	}
+
+	nextEntityID++
+	slimes = append(slimes, newSlime)
+	return true
+}
 
-			slimes = append(slimes, newSlime)
-			return
+// IsAlive means the slime can act, be targeted and take damage
+func (s *Slime) IsAlive() bool {
+	return s.State == Alive
+}
+
+// IsActive also counts slimes that are still spawning in
+func (s *Slime) IsActive() bool {
+	return s.State == Spawning || s.State == Alive
+}
+
+func (s *Slime) Center() rl.Vector2 {
+	return rl.NewVector2(s.Dest.X+s.Dest.Width/2, s.Dest.Y+s.Dest.Height/2)
+}
+
+// feet is the hitbox center, the point that collides with tiles
+func (s *Slime) feet() rl.Vector2 {
+	return rl.NewVector2(s.Dest.X+s.Dest.Width/2, s.Dest.Y+s.Dest.Height/2+slimeHitBoxYOffset+s.HitBox.Height/2)
+}
+
+func findSlime(id EntityID) int {
+	for i := range slimes {
+		if slimes[i].ID == id {
+			return i
 		}
 	}
+	return -1
 }
 
 func IsLocationOnGround(x, y float32) bool {
@@ -153,90 +263,110 @@ func IsLocationOnGround(x, y float32) bool {
 	return false
 }
 
-func UpdateSlimeSpawning() {
-	spawnTimer++
-	if spawnTimer >= spawnInterval {
-		SpawnSlime()
-		spawnTimer = 0
-	}
-}
-
 func DrawSlimeTexture() {
+	DrawBossTelegraph()
+
 	for i := range slimes {
-		if slimes[i].Health > 0 || slimes[i].IsDead {
-			rl.DrawTexturePro(slimes[i].Sprite, slimes[i].Src, slimes[i].Dest, rl.NewVector2(0, 0), 0, rl.White)
-			if slimes[i].Health > 0 {
-				DrawSlimeHealthBar(i)
-			}
+		if slimes[i].State == Despawned {
+			continue
+		}
+
+		tint := slimes[i].Type.TintColor()
+		if slimes[i].aiState == Stunned && slimes[i].IsAlive() {
+			tint = rl.NewColor(255, 140, 140, 255) // hurt flash
+		}
+		if slimes[i].State == Spawning {
+			tint = rl.Fade(tint, float32(slimes[i].SpawnTimer)/float32(spawnDuration))
+		}
+
+		slimes[i].animator.Draw(slimes[i].Dest, tint)
+		if slimes[i].IsAlive() && slimes[i].Type.Boss == nil {
+			DrawSlimeHealthBar(i)
 		}
 	}
 }
 
-func SlimeMoving(playerPos rl.Vector2, attackPlayerFunc func()) {
+func SlimeMoving(playerPos rl.Vector2, attackPlayerFunc func(source rl.Vector2, damage float32)) {
 	globalFrameCount++
+	buildSpatialIndex()
 
 	for i := range slimes {
-		if slimes[i].Health <= 0 && !slimes[i].IsDead {
+		if slimes[i].State == Despawned {
 			continue
 		}
 
 		slimes[i].OldX, slimes[i].OldY = slimes[i].Dest.X, slimes[i].Dest.Y
-		slimes[i].Src.X = slimes[i].Src.Width * float32(slimes[i].Frame)
-
-		if slimes[i].FrameCount%12 == 1 {
-			slimes[i].Frame++
-		}
-
-		if slimes[i].IsDead {
-			if slimes[i].Frame >= 3 {
-				slimes[i].Frame = 0
-			}
-		} else {
-			if slimes[i].Frame >= 5 {
-				slimes[i].Frame = 0
-			}
-		}
 
-		if slimes[i].IsAttacking {
-			if slimes[i].Frame >= 6 {
-				slimes[i].Frame = 0
-			}
-		} else {
-			if slimes[i].Frame >= 5 {
-				slimes[i].Frame = 0
-			}
+		clip := moveClip
+		if slimes[i].State == Dying {
+			clip = deathClip
+		} else if slimes[i].IsAttacking {
+			clip = slimes[i].attackAnim
 		}
+		slimes[i].animator.Play(clip)
+		slimes[i].animator.Update()
 
 		slimes[i].FrameCount++
 
-		if slimes[i].IsDead {
-			slimes[i].Dir = 4
+		switch slimes[i].State {
+		case Spawning:
+			slimes[i].SpawnTimer++
+			if slimes[i].SpawnTimer >= spawnDuration {
+				slimes[i].State = Alive
+			}
+		case Dying:
 			slimes[i].DeathTimer++
 			if slimes[i].DeathTimer >= deathDuration {
-				slimes[i].IsDead = false
-				slimes[i].DeathTimer = 0
+				slimes[i].State = Despawned
 			}
-		} else if slimes[i].IsAttacking {
-			slimes[i].Dir = 3
-		} else {
-			slimes[i].Dir = 2
 		}
 
-		slimes[i].Src.Y = slimes[i].Src.Height * float32(slimes[i].Dir)
-
-		if !slimes[i].IsDead {
+		if slimes[i].IsAlive() {
 			UpdateSlimeAI(i, playerPos, attackPlayerFunc)
+			applySteering(i)
+		}
+
+		// Knockback also slides dead slimes so the last hit still feels heavy
+		if slimes[i].knockback.Active() {
+			dx, dy := slimes[i].knockback.Step()
+			slimes[i].Dest.X += dx
+			slimes[i].Dest.Y += dy
 		}
 
 		slimes[i].HitBox.X = slimes[i].Dest.X + (slimes[i].Dest.Width / 2) - slimes[i].HitBox.Width/2
 		slimes[i].HitBox.Y = slimes[i].Dest.Y + (slimes[i].Dest.Height / 2) + slimeHitBoxYOffset
 
-		SlimeCollision(i, world.GroundTiles)
+		// Amphibious enemies walk over islands, swimmers bump into them like the player
+		if slimes[i].Type.Movement != nav.Amphibious && SlimeCollision(i, world.GroundTiles) {
+			slimes[i].knockback.Stop()
+		}
+
+		slimes[i].vel = rl.NewVector2(slimes[i].Dest.X-slimes[i].OldX, slimes[i].Dest.Y-slimes[i].OldY)
 	}
+
+	updateBossState()
+	compactSlimes()
 }
 
-func SlimeCollision(slimeIndex int, tiles []world.Tile) {
+// compactSlimes drops despawned slimes in place, order is kept so drawing doesn't flicker
+func compactSlimes() {
+	alive := slimes[:0]
+	for i := range slimes {
+		if slimes[i].State != Despawned {
+			alive = append(alive, slimes[i])
+		}
+	}
+
+	// Clear the tail so dropped slimes don't keep textures or pointers alive
+	for i := len(alive); i < len(slimes); i++ {
+		slimes[i] = Slime{}
+	}
+	slimes = alive
+}
+
+func SlimeCollision(slimeIndex int, tiles []world.Tile) bool {
 	var jsonMap = world.WorldMap
+	collided := false
 
 	for i := 0; i < len(tiles); i++ {
 		if slimes[slimeIndex].HitBox.X < float32(tiles[i].X*jsonMap.TileSize+jsonMap.TileSize) &&
@@ -246,26 +376,30 @@ func SlimeCollision(slimeIndex int, tiles []world.Tile) {
 
 			slimes[slimeIndex].Dest.X = slimes[slimeIndex].OldX
 			slimes[slimeIndex].Dest.Y = slimes[slimeIndex].OldY
+			collided = true
 		}
 	}
+
+	return collided
 }
 
 func UnloadSlimeTexture() {
-	rl.UnloadTexture(slimeSprite)
-	rl.UnloadTexture(slimeHealthBarTexture)
+	unloadEnemyTextures()
+	asset.Release("ui.enemy_health")
 }
 
 func DrawSlimeHealthBar(slimeIndex int) {
-	if slimes[slimeIndex].Health <= 0 {
+	if !slimes[slimeIndex].IsAlive() {
 		return
 	}
 
 	slimeHealthBarSrc.Y = slimeHealthBarSrc.Height * float32(slimes[slimeIndex].HealthbarDir)
 
-	healthBarX := slimes[slimeIndex].Dest.X + (slimes[slimeIndex].Dest.Width / 2) - (slimeHealthBarWidth / 2)
+	barWidth := slimeHealthBarWidth * slimes[slimeIndex].Type.Scale
+	healthBarX := slimes[slimeIndex].Dest.X + (slimes[slimeIndex].Dest.Width / 2) - (barWidth / 2)
 	healthBarY := slimes[slimeIndex].Dest.Y - slimeHealthBarOffset
 
-	slimeHealthBarDest := rl.NewRectangle(healthBarX, healthBarY, slimeHealthBarWidth, slimeHealthBarHeight)
+	slimeHealthBarDest := rl.NewRectangle(healthBarX, healthBarY, barWidth, slimeHealthBarHeight)
 
 	rl.DrawTexturePro(slimeHealthBarTexture, slimeHealthBarSrc, slimeHealthBarDest, rl.NewVector2(0, 0), 0, rl.White)
 }
@@ -273,7 +407,7 @@ func DrawSlimeHealthBar(slimeIndex int) {
 func GetSlimePositions() []rl.Vector2 {
 	var positions []rl.Vector2
 	for i := range slimes {
-		if slimes[i].Health > 0 && !slimes[i].IsDead {
+		if slimes[i].IsAlive() {
 			positions = append(positions, rl.NewVector2(slimes[i].Dest.X, slimes[i].Dest.Y))
 		}
 	}
@@ -282,51 +416,90 @@ func GetSlimePositions() []rl.Vector2 {
 
 func GetSlimePosition() rl.Vector2 {
 	for i := range slimes {
-		if slimes[i].Health > 0 && !slimes[i].IsDead {
+		if slimes[i].IsAlive() {
 			return rl.NewVector2(slimes[i].Dest.X, slimes[i].Dest.Y)
 		}
 	}
 	return rl.NewVector2(0, 0)
 }
 
-func GetSlimePositionByIndex(index int) rl.Vector2 {
-	if index < 0 || index >= len(slimes) || slimes[index].Health <= 0 || slimes[index].IsDead {
-		return rl.NewVector2(0, 0)
+func GetSlimePositionByID(id EntityID) (rl.Vector2, bool) {
+	index := findSlime(id)
+	if index < 0 || !slimes[index].IsAlive() {
+		return rl.NewVector2(0, 0), false
+	}
+	return rl.NewVector2(slimes[index].Dest.X, slimes[index].Dest.Y), true
+}
+
+func GetSlimeHitBox(id EntityID) (rl.Rectangle, bool) {
+	index := findSlime(id)
+	if index < 0 || !slimes[index].IsAlive() {
+		return rl.Rectangle{}, false
 	}
-	return rl.NewVector2(slimes[index].Dest.X, slimes[index].Dest.Y)
+	return slimes[index].HitBox, true
+}
+
+func GetSlimeCenter(id EntityID) (rl.Vector2, bool) {
+	index := findSlime(id)
+	if index < 0 || slimes[index].State == Despawned {
+		return rl.NewVector2(0, 0), false
+	}
+	return slimes[index].Center(), true
+}
+
+// GetSlimeCount includes slimes that are spawning or dying
+func GetSlimeCount() int {
+	return len(slimes)
+}
+
+func GetAliveCount() int {
+	count := 0
+	for i := range slimes {
+		if slimes[i].IsActive() {
+			count++
+		}
+	}
+	return count
 }
 
 func IsSlimeAlive() bool {
 	for i := range slimes {
-		if slimes[i].Health > 0 && !slimes[i].IsDead {
+		if slimes[i].IsAlive() {
 			return true
 		}
 	}
 	return false
 }
 
-func GetClosestSlimeIndex(playerPos rl.Vector2) int {
-	closestIndex := -1
+func GetClosestSlimeID(playerPos rl.Vector2) (EntityID, bool) {
+	var closestID EntityID
+	found := false
 	closestDistance := float32(999999)
 
 	for i := range slimes {
-		if slimes[i].Health > 0 && !slimes[i].IsDead {
-			distance := rl.Vector2Distance(playerPos, rl.NewVector2(slimes[i].Dest.X, slimes[i].Dest.Y))
+		if slimes[i].IsAlive() {
+			distance := rl.Vector2Distance(playerPos, slimes[i].Center())
 			if distance < closestDistance {
 				closestDistance = distance
-				closestIndex = i
+				closestID = slimes[i].ID
+				found = true
 			}
 		}
 	}
 
-	return closestIndex
+	return closestID, found
 }
 
-func DamageSlime(slimeIndex int, damage float32, killCounterFunc func()) {
-	if slimeIndex < 0 || slimeIndex >= len(slimes) {
+// DamageSlime does nothing if the slime is already dying or gone
+func DamageSlime(id EntityID, damage float32, killCounterFunc func(xp int)) {
+	index := findSlime(id)
+	if index < 0 || !slimes[index].IsAlive() {
 		return
 	}
+	damageSlime(index, damage, killCounterFunc)
+}
 
+func damageSlime(slimeIndex int, damage float32, killCounterFunc func(xp int)) {
 	wasAlive := slimes[slimeIndex].Health > 0
 
 	slimes[slimeIndex].Health -= damage
@@ -334,161 +507,104 @@ func DamageSlime(slimeIndex int, damage float32, killCounterFunc func()) {
 		slimes[slimeIndex].Health = 0
 	}
 
+	slimeCenter := slimes[slimeIndex].Center()
+
 	if wasAlive && slimes[slimeIndex].Health <= 0 {
-		slimes[slimeIndex].IsDead = true
+		dropLoot(slimes[slimeIndex].Type, slimeCenter)
+		slimes[slimeIndex].State = Dying
+		slimes[slimeIndex].IsAttacking = false
 		slimes[slimeIndex].DeathTimer = 0
-		killCounterFunc()
+		killCounterFunc(slimes[slimeIndex].Type.XP)
+		audio.PlayAt(audio.SlimeDeath, slimeCenter)
+		fx.Burst(&fx.JellySplat, slimeCenter.X, slimeCenter.Y)
+	} else if wasAlive {
+		audio.PlayAt(audio.SlimeHit, slimeCenter)
 	}
 
-	healthPercentage := slimes[slimeIndex].Health / slimes[slimeIndex].MaxHealth
+	slimes[slimeIndex].updateHealthBar()
+}
+
+// updateHealthBar picks the health bar frame for the current health
+func (s *Slime) updateHealthBar() {
+	healthPercentage := s.Health / s.MaxHealth
 	if healthPercentage > 0.875 {
-		slimes[slimeIndex].HealthbarDir = 0
+		s.HealthbarDir = 0
 	} else if healthPercentage > 0.75 {
-		slimes[slimeIndex].HealthbarDir = 1
+		s.HealthbarDir = 1
 	} else if healthPercentage > 0.625 {
-		slimes[slimeIndex].HealthbarDir = 2
+		s.HealthbarDir = 2
 	} else if healthPercentage > 0.5 {
-		slimes[slimeIndex].HealthbarDir = 3
+		s.HealthbarDir = 3
 	} else if healthPercentage > 0.375 {
-		slimes[slimeIndex].HealthbarDir = 4
+		s.HealthbarDir = 4
 	} else if healthPercentage > 0.25 {
-		slimes[slimeIndex].HealthbarDir = 5
+		s.HealthbarDir = 5
 	} else if healthPercentage > 0.125 {
-		slimes[slimeIndex].HealthbarDir = 6
+		s.HealthbarDir = 6
 	} else {
-		slimes[slimeIndex].HealthbarDir = 7
+		s.HealthbarDir = 7
 	}
 }
 
-func UpdateSlimeAI(slimeIndex int, playerPos rl.Vector2, attackPlayerFunc func()) {
-	slime := &slimes[slimeIndex]
-	slimePos := rl.NewVector2(slime.Dest.X, slime.Dest.Y)
-	dist := rl.Vector2Distance(slimePos, playerPos)
-
-	slime.stateTimer++
-
-	switch slime.aiState {
-	case Wandering:
-		slime.wanderTimer--
-		if slime.wanderTimer <= 0 {
-			radius := rand.Float32() * slime.patrolRadius
-			slime.targetX = slime.Dest.X + float32(radius)*float32(rl.GetRandomValue(-1, 1))
-			slime.targetY = slime.Dest.Y + float32(radius)*float32(rl.GetRandomValue(-1, 1))
-			slime.wanderTimer = rand.Intn(180) + 120
-		}
-
-		// Sometimes just pause and look around
-		if rand.Float32() < 0.08 {
-			return
+// ApplyHitbox damages every living slime inside the hitbox that it hasn't hit yet
+func ApplyHitbox(hitbox *combat.Hitbox, killCounterFunc func(xp int)) int {
+	hits := 0
+	for i := range slimes {
+		if !slimes[i].IsAlive() {
+			continue
 		}
-
-		if slime.targetX != slime.Dest.X || slime.targetY != slime.Dest.Y {
-			dirX := slime.targetX - slime.Dest.X
-			dirY := slime.targetY - slime.Dest.Y
-			length := rl.Vector2Length(rl.NewVector2(dirX, dirY))
-			if length > 2 {
-				// Lazy movement with random hesitation
-				speed := float32(0.15) + rand.Float32()*0.25
-				slime.Dest.X += (dirX / length) * speed
-				slime.Dest.Y += (dirY / length) * speed
+		if hitbox.Overlaps(slimes[i].HitBox) && hitbox.TryHit(int(slimes[i].ID)) {
+			damageSlime(i, hitbox.Damage, killCounterFunc)
+
+			// Bosses shrug off knockback and stun
+			if slimes[i].Type.Boss == nil {
+				center := slimes[i].Center()
+				slimes[i].knockback = combat.Knockback(hitbox.Origin(center), center, hitbox.Damage)
+				stunSlime(i, combat.HitStunFrames(hitbox.Damage))
 			}
+			hits++
 		}
+	}
+	return hits
+}
 
-		if dist < slime.aggroRange {
-			slime.aiState = Chasing
-			slime.stateTimer = 0
-		}
-
-	case Chasing:
-		if dist <= attackRange && globalFrameCount-slime.LastAttack >= attackCooldown {
-			slime.aiState = Attacking
-			slime.stateTimer = 0
-		} else if dist < 150 && dist > 8 {
-			directionX := playerPos.X - slime.Dest.X
-			directionY := playerPos.Y - slime.Dest.Y
-
-			length := rl.Vector2Length(rl.NewVector2(directionX, directionY))
-			if length > 0 {
-				directionX /= length
-				directionY /= length
-			}
-
-			// Natural movement - sometimes hesitate or overshoot
-			hesitation := rand.Float32()
-			if hesitation < 0.1 {
-				return // pause like real animals
-			} else if hesitation < 0.2 {
-				directionX += (rand.Float32() - 0.5) * 0.3
-				directionY += (rand.Float32() - 0.5) * 0.3
-			}
-
-			// Speed based on health (hurt = more desperate)
-			urgency := (slime.MaxHealth - slime.Health) / slime.MaxHealth
-			baseSpeed := float32(0.6) + urgency*0.4
-			moveSpeed := baseSpeed + (rand.Float32()-0.5)*0.2
-
-			slime.Dest.X += directionX * moveSpeed
-			slime.Dest.Y += directionY * moveSpeed
-		} else if dist > slime.aggroRange*1.5 {
-			slime.aiState = Wandering
-			slime.wanderTimer = 30
-		}
+// StunSlime interrupts whatever the slime was doing
+func StunSlime(id EntityID, frames int) {
+	if index := findSlime(id); index >= 0 {
+		stunSlime(index, frames)
+	}
+}
 
-	case Attacking:
-		if !slime.IsAttacking {
-			slime.LastAttack = globalFrameCount
-			slime.IsAttacking = true
-			slime.AttackTimer = attackDuration
-		}
+func stunSlime(slimeIndex int, frames int) {
+	slime := &slimes[slimeIndex]
+	if !slime.IsAlive() || slime.Type.Boss != nil {
+		return
+	}
 
-		if slime.IsAttacking {
-			slime.AttackTimer--
-			if slime.AttackTimer <= attackDuration-3 && slime.AttackTimer > attackDuration-6 {
-				attackPlayerFunc()
-			}
-			if slime.AttackTimer <= 0 {
-				slime.IsAttacking = false
-				// Usually keep fighting, sometimes back off briefly
-				if rand.Intn(100) < 90 {
-					slime.aiState = Chasing
-				} else {
-					slime.aiState = Retreating
-				}
-				slime.stateTimer = 0
-			}
-		}
+	stunBrain(slime, frames)
+}
 
-	case Retreating:
-		if dist < 60 {
-			directionX := slime.Dest.X - playerPos.X
-			directionY := slime.Dest.Y - playerPos.Y
-
-			length := rl.Vector2Length(rl.NewVector2(directionX, directionY))
-			if length > 0 {
-				directionX /= length
-				directionY /= length
-			}
+// UpdateSlimeAI feeds the blackboard and runs the enemy's behavior for one frame
+func UpdateSlimeAI(slimeIndex int, playerPos rl.Vector2, attackPlayerFunc func(source rl.Vector2, damage float32)) {
+	slime := &slimes[slimeIndex]
+	if slime.Type.Boss != nil {
+		updateBossAI(slimeIndex, playerPos, attackPlayerFunc)
+		return
+	}
 
-			retreatSpeed := float32(1.0)
-			slime.Dest.X += directionX * retreatSpeed
-			slime.Dest.Y += directionY * retreatSpeed
-		} else {
-			slime.aiState = Wandering
-			slime.wanderTimer = rand.Intn(60) + 30
-		}
+	board := slime.brain.Board()
+	board.Set(bbDist, rl.Vector2Distance(rl.NewVector2(slime.Dest.X, slime.Dest.Y), playerPos))
+	board.Set(bbPlayerX, playerPos.X)
+	board.Set(bbPlayerY, playerPos.Y)
 
-		// Quick return to fighting
-		if slime.stateTimer > 90 {
-			slime.aiState = Chasing
-			slime.stateTimer = 0
-		}
-	}
+	attackPlayer = attackPlayerFunc
+	slime.Type.behavior.Update(slime, &slime.brain)
+	slime.aiState = AIState(slime.Type.behavior.Tag(&slime.brain))
 }
 
 func ResetSlimes() {
-	slimes = []Slime{}
-	spawnTimer = 0
+	slimes = slimes[:0]
 	globalFrameCount = 0
-
-	SpawnSlime()
+	nextEntityID = 1
+	resetBoss()
 }
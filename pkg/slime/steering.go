@@ -0,0 +1,151 @@
+package slime
+
+import (
+	"axelot/pkg/rng"
+	"math"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// SteeringWeights blends the steering behaviours, each AI state has its own set
+type SteeringWeights struct {
+	Separation float32 // push away from close neighbours, in pixels per frame
+	Cohesion   float32 // towards the middle of the group
+	Alignment  float32 // move the same way as the group
+	Seek       float32 // towards the AI target
+	Flee       float32 // away from the AI threat
+	Wander     float32 // slow random drift
+}
+
+var steeringWeights = map[AIState]SteeringWeights{
+	Wandering:  {Separation: 0.6, Cohesion: 0.2, Alignment: 0.2, Seek: 1, Wander: 0.5},
+	Chasing:    {Separation: 0.9, Alignment: 0.1, Seek: 1, Flee: 1, Wander: 0.1},
+	Attacking:  {Separation: 0.4},
+	Retreating: {Separation: 0.6, Flee: 1, Wander: 0.2},
+	Stunned:    {Separation: 0.3},
+}
+
+var (
+	separationRadius float32 = 18
+	neighbourRadius  float32 = 48
+	wanderJitter     float32 = 0.6
+
+	// Spatial hash so neighbour lookups only look at nearby cells
+	spatialCellSize float32 = 48
+	spatialCells            = map[[2]int][]int{}
+	neighbourBuf    []int
+)
+
+// intent is what the AI wants this frame, applySteering turns it into movement
+type intent struct {
+	seek  rl.Vector2 // normalized, zero for none
+	flee  rl.Vector2
+	speed float32
+}
+
+func spatialCell(pos rl.Vector2) [2]int {
+	return [2]int{int(math.Floor(float64(pos.X / spatialCellSize))), int(math.Floor(float64(pos.Y / spatialCellSize)))}
+}
+
+// buildSpatialIndex buckets the slimes by position, done once per frame before the AI runs
+func buildSpatialIndex() {
+	for key := range spatialCells {
+		spatialCells[key] = spatialCells[key][:0]
+	}
+	for i := range slimes {
+		if !slimes[i].IsActive() {
+			continue
+		}
+		key := spatialCell(slimes[i].Center())
+		spatialCells[key] = append(spatialCells[key], i)
+	}
+}
+
+// queryNeighbours appends the indices of active slimes within radius of pos, radius must not exceed the cell size
+func queryNeighbours(pos rl.Vector2, radius float32, out []int) []int {
+	center := spatialCell(pos)
+	for dy := -1; dy <= 1; dy++ {
+		for dx := -1; dx <= 1; dx++ {
+			for _, i := range spatialCells[[2]int{center[0] + dx, center[1] + dy}] {
+				if slimes[i].IsActive() && rl.Vector2Distance(pos, slimes[i].Center()) <= radius {
+					out = append(out, i)
+				}
+			}
+		}
+	}
+	return out
+}
+
+// applySteering moves a slime by its AI intent blended with the crowd around it
+func applySteering(index int) {
+	slime := &slimes[index]
+	w := steeringWeights[slime.aiState]
+	pos := slime.Center()
+
+	var separation, cohesion, alignment rl.Vector2
+	neighbourBuf = queryNeighbours(pos, neighbourRadius, neighbourBuf[:0])
+	groupCenter := rl.Vector2{}
+	groupVel := rl.Vector2{}
+	groupSize := 0
+	for _, j := range neighbourBuf {
+		if j == index {
+			continue
+		}
+		other := slimes[j].Center()
+		dist := rl.Vector2Distance(pos, other)
+
+		if dist < separationRadius {
+			away := rl.NewVector2(pos.X-other.X, pos.Y-other.Y)
+			if dist == 0 {
+				// Exactly stacked, split them by ID so both don't pick the same side
+				angle := float64(slime.ID) * 2.39996
+				away = rl.NewVector2(float32(math.Cos(angle)), float32(math.Sin(angle)))
+				dist = 1
+			}
+			separation = rl.Vector2Add(separation, rl.Vector2Scale(away, (1-dist/separationRadius)/dist))
+		}
+
+		groupCenter = rl.Vector2Add(groupCenter, other)
+		groupVel = rl.Vector2Add(groupVel, slimes[j].vel)
+		groupSize++
+	}
+	if groupSize > 0 {
+		groupCenter = rl.Vector2Scale(groupCenter, 1/float32(groupSize))
+		cohesion = rl.Vector2Normalize(rl.Vector2Subtract(groupCenter, pos))
+		alignment = rl.Vector2Normalize(groupVel)
+	}
+
+	var move rl.Vector2
+	if slime.intent.speed > 0 {
+		slime.wanderAngle += (rng.Float32() - 0.5) * wanderJitter
+		wander := rl.NewVector2(float32(math.Cos(float64(slime.wanderAngle))), float32(math.Sin(float64(slime.wanderAngle))))
+
+		force := rl.Vector2Scale(slime.intent.seek, w.Seek)
+		force = rl.Vector2Add(force, rl.Vector2Scale(slime.intent.flee, w.Flee))
+		force = rl.Vector2Add(force, rl.Vector2Scale(wander, w.Wander))
+		force = rl.Vector2Add(force, rl.Vector2Scale(cohesion, w.Cohesion))
+		force = rl.Vector2Add(force, rl.Vector2Scale(alignment, w.Alignment))
+		if rl.Vector2Length(force) > 0 {
+			move = rl.Vector2Scale(rl.Vector2Normalize(force), slime.intent.speed)
+		}
+	}
+
+	// Separation is a push rather than a direction so standing slimes still spread out
+	if rl.Vector2Length(separation) > 1 {
+		separation = rl.Vector2Normalize(separation)
+	}
+	move = rl.Vector2Add(move, rl.Vector2Scale(separation, w.Separation))
+
+	slime.Dest.X += move.X
+	slime.Dest.Y += move.Y
+	slime.intent = intent{}
+}
+
+// surroundPoint is where a melee slime wants to stand, on a ring around the player at its current bearing
+func surroundPoint(slime *Slime, playerCenter rl.Vector2) rl.Vector2 {
+	bearing := rl.Vector2Normalize(rl.Vector2Subtract(slime.Center(), playerCenter))
+	if rl.Vector2Length(bearing) == 0 {
+		bearing = rl.NewVector2(1, 0)
+	}
+	return rl.Vector2Add(playerCenter, rl.Vector2Scale(bearing, slime.Type.Attack.Range*0.6))
+}
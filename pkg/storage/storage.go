@@ -0,0 +1,57 @@
+package storage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Dir is where save files live, set once at startup
+var Dir = defaultDir()
+
+func defaultDir() string {
+	if base, err := os.UserConfigDir(); err == nil {
+		return filepath.Join(base, "axelot")
+	}
+	return "."
+}
+
+func Path(name string) string {
+	return filepath.Join(Dir, name)
+}
+
+// WriteFileAtomic writes to a temp file next to path and renames it over,
+// so a crash mid-write leaves either the old or the new file, never half of one
+func WriteFileAtomic(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+"-*.tmp")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmpName, path)
+	}
+	if err != nil {
+		os.Remove(tmpName)
+		return fmt.Errorf("saving %s: %w", path, err)
+	}
+	return nil
+}
+
+// Quarantine moves a file that can't be read out of the way, keeping it for inspection
+func Quarantine(path string) {
+	os.Rename(path, path+".bad")
+}
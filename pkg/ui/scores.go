@@ -0,0 +1,147 @@
+package ui
+
+import (
+	"axelot/pkg/scores"
+	"fmt"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// StartNameEntry asks for a name on the game over screen, prefilled with the last one used
+func StartNameEntry(name string) {
+	enteringName = true
+	nameBuffer = name
+}
+
+func GetEnteredName() string {
+	return nameBuffer
+}
+
+// Used when Enter is pressed without typing a name
+const defaultName = "Axolotl"
+
+func handleNameInput() MenuOption {
+	for char := rl.GetCharPressed(); char > 0; char = rl.GetCharPressed() {
+		if char >= 32 && char < 127 && len(nameBuffer) < maxNameLen {
+			nameBuffer += string(rune(char))
+		}
+	}
+	if rl.IsKeyPressed(rl.KeyBackspace) && len(nameBuffer) > 0 {
+		nameBuffer = nameBuffer[:len(nameBuffer)-1]
+	}
+	if rl.IsKeyPressed(rl.KeyEnter) {
+		if nameBuffer == "" {
+			nameBuffer = defaultName
+		}
+		enteringName = false
+		return SubmitName
+	}
+	// Esc leaves the run off the board, it stays in the history unnamed
+	if rl.IsKeyPressed(rl.KeyEscape) {
+		enteringName = false
+	}
+	return MenuOption(-1)
+}
+
+func drawNameEntry(screenWidth float32) {
+	label := "NEW HIGH SCORE! Name:"
+	rl.DrawText(label, 110, 210, 20, rl.Gold)
+
+	boxX := int32(110 + rl.MeasureText(label, 20) + 10)
+	rl.DrawRectangle(boxX, 206, 150, 28, rl.NewColor(20, 30, 50, 230))
+	rl.DrawRectangleLines(boxX, 206, 150, 28, rl.Gold)
+
+	text := nameBuffer
+	// Blinking cursor
+	if (int(rl.GetTime()*2))%2 == 0 {
+		text += "_"
+	}
+	rl.DrawText(text, boxX+6, 210, 20, rl.White)
+}
+
+// formatTime turns frames into m:ss
+func formatTime(frames int) string {
+	seconds := frames / 60
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+}
+
+func DrawHighScores(screenWidth, screenHeight float32) {
+	title := "HIGH SCORES"
+	rl.DrawText(title, int32(screenWidth/2)-rl.MeasureText(title, 40)/2, 40, 40, rl.White)
+
+	// Tabs for each sort order
+	tabX := int32(150)
+	for _, key := range scores.SortKeys {
+		color := rl.Gray
+		if key == scoreView {
+			color = rl.Yellow
+		}
+		rl.DrawText(key.String(), tabX, 100, 20, color)
+		tabX += 110
+	}
+
+	rl.DrawText("#", 60, 140, 16, rl.LightGray)
+	rl.DrawText("Name", 90, 140, 16, rl.LightGray)
+	rl.DrawText("Kills", 280, 140, 16, rl.LightGray)
+	rl.DrawText("Time", 360, 140, 16, rl.LightGray)
+	rl.DrawText("Combo", 450, 140, 16, rl.LightGray)
+
+	top := scores.Top(scoreView)
+	if len(top) == 0 {
+		rl.DrawText("No runs yet", int32(screenWidth/2)-rl.MeasureText("No runs yet", 20)/2, 200, 20, rl.Gray)
+	}
+	for i, e := range top {
+		y := int32(170 + i*30)
+		color := rl.White
+		if e.Victory {
+			color = rl.Gold
+		}
+		rl.DrawText(fmt.Sprintf("%d", i+1), 60, y, 18, color)
+		rl.DrawText(e.Name, 90, y, 18, color)
+		rl.DrawText(fmt.Sprintf("%d", e.Kills), 280, y, 18, color)
+		rl.DrawText(formatTime(e.Time), 360, y, 18, color)
+		rl.DrawText(fmt.Sprintf("%d", e.MaxCombo), 450, y, 18, color)
+	}
+
+	rl.DrawText("> Back", int32(screenWidth/2)-rl.MeasureText("> Back", 24)/2, int32(screenHeight-90), 24, rl.Yellow)
+	rl.DrawText("A/D: Sort  •  Enter: Back", 190, int32(screenHeight-40), 16, rl.Gray)
+}
+
+func DrawRunHistory(screenWidth, screenHeight float32) {
+	title := "RUN HISTORY"
+	rl.DrawText(title, int32(screenWidth/2)-rl.MeasureText(title, 40)/2, 40, 40, rl.White)
+
+	rl.DrawText("Date", 20, 100, 16, rl.LightGray)
+	rl.DrawText("Seed", 170, 100, 16, rl.LightGray)
+	rl.DrawText("Kills", 300, 100, 16, rl.LightGray)
+	rl.DrawText("Time", 360, 100, 16, rl.LightGray)
+	rl.DrawText("Combo", 430, 100, 16, rl.LightGray)
+	rl.DrawText("Lv", 500, 100, 16, rl.LightGray)
+
+	history := scores.History()
+	if len(history) == 0 {
+		rl.DrawText("No runs yet", int32(screenWidth/2)-rl.MeasureText("No runs yet", 20)/2, 160, 20, rl.Gray)
+	}
+	for row := 0; row < historyRows && historyScroll+row < len(history); row++ {
+		e := history[historyScroll+row]
+		y := int32(125 + row*26)
+		color := rl.White
+		if e.Victory {
+			color = rl.Gold
+		}
+		rl.DrawText(e.Date.Local().Format("01-02 15:04"), 20, y, 16, color)
+		rl.DrawText(fmt.Sprintf("%08x", uint32(e.Seed)), 170, y, 16, color)
+		rl.DrawText(fmt.Sprintf("%d", e.Kills), 300, y, 16, color)
+		rl.DrawText(formatTime(e.Time), 360, y, 16, color)
+		rl.DrawText(fmt.Sprintf("%d", e.MaxCombo), 430, y, 16, color)
+		rl.DrawText(fmt.Sprintf("%d", e.Level), 500, y, 16, color)
+	}
+
+	if len(history) > historyRows {
+		more := fmt.Sprintf("%d-%d of %d", historyScroll+1, min(historyScroll+historyRows, len(history)), len(history))
+		rl.DrawText(more, int32(screenWidth)-20-rl.MeasureText(more, 14), int32(screenHeight-110), 14, rl.Gray)
+	}
+
+	rl.DrawText("> Back", int32(screenWidth/2)-rl.MeasureText("> Back", 24)/2, int32(screenHeight-90), 24, rl.Yellow)
+	rl.DrawText("W/S: Scroll  •  Enter: Back", 180, int32(screenHeight-40), 16, rl.Gray)
+}
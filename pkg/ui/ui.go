@@ -1,6 +1,9 @@
 package ui
 
 import (
+	"axelot/pkg/profile"
+	"axelot/pkg/scores"
+	"axelot/pkg/upgrade"
 	"fmt"
 
 	rl "github.com/gen2brain/raylib-go/raylib"
@@ -14,6 +17,11 @@ const (
 	Paused
 	GameOver
 	Settings
+	Victory
+	LevelUp // game paused while an upgrade is picked
+	Unlocks // permanent upgrades bought between runs
+	HighScores
+	RunHistory
 )
 
 type MenuOption int
@@ -27,13 +35,27 @@ const (
 	VolumeUp
 	VolumeDown
 	ToggleFullscreen
+	PickUpgrade
+	UnlocksMenu
+	BuyUnlock
+	HighScoresMenu
+	HistoryMenu
+	SubmitName
+	WatchReplay
+	SaveAndQuit
+	ContinueGame
 )
 
 var (
 	currentState   GameState = MainMenu
 	selectedOption int       = 0
 	menuOptions    []string
-	showStats      bool = false
+	menuActions    []MenuOption // what each option does, for menus built with addOption
+	showStats      bool         = false
+
+	// Shown under the title, e.g. when a save can't be loaded
+	menuMessage       string
+	continueAvailable bool
 
 	// Settings
 	masterVolume float32 = 0.7
@@ -43,6 +65,23 @@ var (
 	finalKillCount int
 	survivalTime   int
 	finalComboMax  int
+	runReward      int
+
+	// Level up choices
+	upgradeOffers []*upgrade.Def
+	offerLevel    int
+
+	// Result of the last purchase on the unlock screen
+	unlockMessage string
+
+	// Name entry on the game over screen for a new high score
+	enteringName bool
+	nameBuffer   string
+	maxNameLen   = 12
+
+	scoreView     scores.SortKey
+	historyScroll int
+	historyRows   = 14
 )
 
 func GetCurrentState() GameState {
@@ -52,20 +91,64 @@ func GetCurrentState() GameState {
 func SetGameState(state GameState) {
 	currentState = state
 	selectedOption = 0
+	menuMessage = ""
 
 	// Set appropriate menu options for each state
 	switch state {
 	case MainMenu:
-		menuOptions = []string{"Start Game", "Settings", "Quit"}
+		menuOptions, menuActions = nil, nil
+		if continueAvailable {
+			addOption("Continue", ContinueGame)
+		}
+		addOption("Start Game", StartGame)
+		addOption("Watch Replay", WatchReplay)
+		addOption("Unlocks", UnlocksMenu)
+		addOption("High Scores", HighScoresMenu)
+		addOption("Run History", HistoryMenu)
+		addOption("Settings", SettingsMenu)
+		addOption("Quit", QuitGame)
 	case Paused:
-		menuOptions = []string{"Resume", "Settings", "Main Menu"}
+		menuOptions, menuActions = nil, nil
+		addOption("Resume", ResumeGame)
+		addOption("Save & Quit", SaveAndQuit)
+		addOption("Settings", SettingsMenu)
+		addOption("Main Menu", BackToMenu)
 	case GameOver:
 		menuOptions = []string{"Try Again", "Main Menu", "Quit"}
+	case Victory:
+		menuOptions = []string{"Play Again", "Main Menu", "Quit"}
 	case Settings:
 		menuOptions = []string{"Volume: " + fmt.Sprintf("%.0f%%", masterVolume*100), "Fullscreen: " + getToggleText(isFullscreen), "Back"}
+	case Unlocks:
+		menuOptions = nil
+		for _, u := range profile.Unlocks() {
+			menuOptions = append(menuOptions, u.Name)
+		}
+		menuOptions = append(menuOptions, "Back")
+		unlockMessage = ""
+	case HighScores, RunHistory:
+		menuOptions = []string{"Back"}
+		historyScroll = 0
+	}
+}
+
+func addOption(label string, action MenuOption) {
+	menuOptions = append(menuOptions, label)
+	menuActions = append(menuActions, action)
+}
+
+// SetContinueAvailable shows "Continue" on the main menu when a saved run exists
+func SetContinueAvailable(available bool) {
+	continueAvailable = available
+	if currentState == MainMenu {
+		SetGameState(MainMenu)
 	}
 }
 
+func SetMenuMessage(message string) {
+	menuMessage = message
+}
+
 func getToggleText(enabled bool) string {
 	if enabled {
 		return "ON"
@@ -74,15 +157,43 @@ func getToggleText(enabled bool) string {
 }
 
 func HandleMenuInput() MenuOption {
-	// Navigation
-	if rl.IsKeyPressed(rl.KeyUp) || rl.IsKeyPressed(rl.KeyW) {
+	if enteringName {
+		return handleNameInput()
+	}
+
+	// Leaderboard tabs and history scrolling
+	switch currentState {
+	case HighScores:
+		if rl.IsKeyPressed(rl.KeyLeft) || rl.IsKeyPressed(rl.KeyA) {
+			scoreView = scores.SortKeys[(int(scoreView)+len(scores.SortKeys)-1)%len(scores.SortKeys)]
+		}
+		if rl.IsKeyPressed(rl.KeyRight) || rl.IsKeyPressed(rl.KeyD) {
+			scoreView = scores.SortKeys[(int(scoreView)+1)%len(scores.SortKeys)]
+		}
+	case RunHistory:
+		if (rl.IsKeyPressed(rl.KeyUp) || rl.IsKeyPressed(rl.KeyW)) && historyScroll > 0 {
+			historyScroll--
+		}
+		if (rl.IsKeyPressed(rl.KeyDown) || rl.IsKeyPressed(rl.KeyS)) && historyScroll+historyRows < len(scores.History()) {
+			historyScroll++
+		}
+	}
+
+	// Navigation, upgrade cards sit side by side
+	prevKey, nextKey := rl.IsKeyPressed(rl.KeyUp) || rl.IsKeyPressed(rl.KeyW), rl.IsKeyPressed(rl.KeyDown) || rl.IsKeyPressed(rl.KeyS)
+	if currentState == LevelUp {
+		prevKey = prevKey || rl.IsKeyPressed(rl.KeyLeft) || rl.IsKeyPressed(rl.KeyA)
+		nextKey = nextKey || rl.IsKeyPressed(rl.KeyRight) || rl.IsKeyPressed(rl.KeyD)
+	}
+
+	if prevKey {
 		selectedOption--
 		if selectedOption < 0 {
 			selectedOption = len(menuOptions) - 1
 		}
 	}
 
-	if rl.IsKeyPressed(rl.KeyDown) || rl.IsKeyPressed(rl.KeyS) {
+	if nextKey {
 		selectedOption++
 		if selectedOption >= len(menuOptions) {
 			selectedOption = 0
@@ -92,33 +203,26 @@ func HandleMenuInput() MenuOption {
 	// Selection
 	if rl.IsKeyPressed(rl.KeyEnter) || rl.IsKeyPressed(rl.KeySpace) {
 		switch currentState {
-		case MainMenu:
+		case MainMenu, Paused:
+			return menuActions[selectedOption]
+		case GameOver, Victory:
 			switch selectedOption {
 			case 0:
 				return StartGame
 			case 1:
-				return SettingsMenu
+				return BackToMenu
 			case 2:
 				return QuitGame
 			}
-		case Paused:
-			switch selectedOption {
-			case 0:
-				return ResumeGame
-			case 1:
-				return SettingsMenu
-			case 2:
-				return BackToMenu
-			}
-		case GameOver:
-			switch selectedOption {
-			case 0:
-				return StartGame
-			case 1:
+		case LevelUp:
+			return PickUpgrade
+		case Unlocks:
+			if selectedOption == len(menuOptions)-1 {
 				return BackToMenu
-			case 2:
-				return QuitGame
 			}
+			return BuyUnlock
+		case HighScores, RunHistory:
+			return BackToMenu
 		case Settings:
 			switch selectedOption {
 			case 0:
@@ -158,6 +262,20 @@ func DrawMenu() {
 
 	// Same dark overlay for all menus
 	rl.DrawRectangle(0, 0, int32(screenWidth), int32(screenHeight), rl.NewColor(0, 0, 0, 150))
+	switch currentState {
+	case LevelUp:
+		DrawLevelUp(screenWidth, screenHeight)
+		return
+	case Unlocks:
+		DrawUnlocks(screenWidth, screenHeight)
+		return
+	case HighScores:
+		DrawHighScores(screenWidth, screenHeight)
+		return
+	case RunHistory:
+		DrawRunHistory(screenWidth, screenHeight)
+		return
+	}
 	DrawStandardMenu(screenWidth, screenHeight)
 }
 
@@ -171,6 +289,8 @@ func DrawStandardMenu(screenWidth, screenHeight float32) {
 		title = "PAUSED"
 	case GameOver:
 		title = "GAME OVER"
+	case Victory:
+		title = "VICTORY"
 	case Settings:
 		title = "SETTINGS"
 	}
@@ -178,10 +298,18 @@ func DrawStandardMenu(screenWidth, screenHeight float32) {
 	titleWidth := rl.MeasureText(title, 48)
 	rl.DrawText(title, int32(screenWidth/2-float32(titleWidth)/2), 150, 48, rl.White)
 
-	// Simple menu options
+	if menuMessage != "" {
+		rl.DrawText(menuMessage, int32(screenWidth/2)-rl.MeasureText(menuMessage, 16)/2, 215, 16, rl.Orange)
+	}
+
+	// Simple menu options, packed tighter when there are many
 	startY := float32(250)
+	spacing := float32(50)
+	if len(menuOptions) > 5 {
+		spacing = 40
+	}
 	for i, option := range menuOptions {
-		y := startY + float32(i)*50
+		y := startY + float32(i)*spacing
 
 		var color rl.Color
 		if i == selectedOption {
@@ -196,10 +324,16 @@ func DrawStandardMenu(screenWidth, screenHeight float32) {
 	}
 
 	// Game Over stats
-	if currentState == GameOver && showStats {
+	if (currentState == GameOver || currentState == Victory) && showStats {
 		DrawGameOverStats()
 	}
 
+	if enteringName {
+		drawNameEntry(screenWidth)
+		rl.DrawText("Type your name  •  Enter: Save  •  Esc: Skip", 130, int32(screenHeight-40), 16, rl.Gray)
+		return
+	}
+
 	// Simple controls hint
 	rl.DrawText("WASD/Arrows: Navigate  •  Enter: Select", 120, int32(screenHeight-40), 16, rl.Gray)
 }
@@ -209,7 +343,7 @@ func DrawGameOverStats() {
 
 	rl.DrawText("FINAL STATS:", 220, int32(statsY), 20, rl.White)
 
-	killText := fmt.Sprintf("Jellyfish Defeated: %d", finalKillCount)
+	killText := fmt.Sprintf("Enemies Defeated: %d", finalKillCount)
 	rl.DrawText(killText, 180, int32(statsY+30), 18, rl.White)
 
 	timeText := fmt.Sprintf("Survival Time: %d seconds", survivalTime/60)
@@ -217,12 +351,16 @@ func DrawGameOverStats() {
 
 	comboText := fmt.Sprintf("Max Combo: %d", finalComboMax)
 	rl.DrawText(comboText, 180, int32(statsY+80), 18, rl.White)
+
+	rewardText := fmt.Sprintf("Shells earned: +%d", runReward)
+	rl.DrawText(rewardText, 180, int32(statsY+105), 18, rl.Gold)
 }
 
-func SetGameOverStats(kills, time, maxCombo int) {
+func SetGameOverStats(kills, time, maxCombo, reward int) {
 	finalKillCount = kills
 	survivalTime = time
 	finalComboMax = maxCombo
+	runReward = reward
 	showStats = true
 }
 
@@ -242,3 +380,138 @@ func SetFullscreen(enabled bool) {
 		menuOptions[1] = "Fullscreen: OFF"
 	}
 }
+
+// ShowLevelUp pauses the game on the upgrade screen
+func ShowLevelUp(level int, offers []*upgrade.Def) {
+	upgradeOffers = offers
+	offerLevel = level
+	SetGameState(LevelUp)
+	menuOptions = make([]string, len(offers))
+	for i, offer := range offers {
+		menuOptions[i] = offer.Name
+	}
+}
+
+// GetSelectedUpgrade is the upgrade under the cursor on the level up screen
+func GetSelectedUpgrade() *upgrade.Def {
+	if selectedOption < 0 || selectedOption >= len(upgradeOffers) {
+		return nil
+	}
+	return upgradeOffers[selectedOption]
+}
+
+func DrawLevelUp(screenWidth, screenHeight float32) {
+	title := fmt.Sprintf("LEVEL %d", offerLevel)
+	titleWidth := rl.MeasureText(title, 48)
+	rl.DrawText(title, int32(screenWidth/2-float32(titleWidth)/2), 120, 48, rl.White)
+
+	subtitle := "Choose an upgrade"
+	rl.DrawText(subtitle, int32(screenWidth/2)-rl.MeasureText(subtitle, 20)/2, 180, 20, rl.LightGray)
+
+	cardWidth, cardHeight, gap := int32(170), int32(200), int32(15)
+	totalWidth := int32(len(upgradeOffers))*cardWidth + int32(len(upgradeOffers)-1)*gap
+	startX := int32(screenWidth)/2 - totalWidth/2
+	cardY := int32(230)
+
+	for i, offer := range upgradeOffers {
+		x := startX + int32(i)*(cardWidth+gap)
+		color := offer.Color()
+
+		y := cardY
+		if i == selectedOption {
+			y -= 10
+		}
+		rl.DrawRectangle(x, y, cardWidth, cardHeight, rl.NewColor(20, 30, 50, 230))
+		rl.DrawRectangleLines(x, y, cardWidth, cardHeight, color)
+		if i == selectedOption {
+			rl.DrawRectangleLines(x-2, y-2, cardWidth+4, cardHeight+4, rl.Yellow)
+		}
+
+		rl.DrawText(offer.Rarity, x+10, y+10, 14, color)
+		rl.DrawText(offer.Name, x+10, y+40, 18, rl.White)
+		rl.DrawText(offer.Description, x+10, y+80, 14, rl.LightGray)
+
+		stackText := fmt.Sprintf("Owned: %d", upgrade.Stacks(offer.ID))
+		if offer.MaxStacks > 0 {
+			stackText = fmt.Sprintf("Owned: %d/%d", upgrade.Stacks(offer.ID), offer.MaxStacks)
+		}
+		rl.DrawText(stackText, x+10, y+cardHeight-26, 14, rl.Gray)
+	}
+
+	rl.DrawText("A/D: Choose  •  Enter: Take", 170, int32(screenHeight-40), 16, rl.Gray)
+}
+
+// GetSelectedUnlock is the id of the unlock under the cursor
+func GetSelectedUnlock() string {
+	list := profile.Unlocks()
+	if selectedOption < 0 || selectedOption >= len(list) {
+		return ""
+	}
+	return list[selectedOption].ID
+}
+
+func SetUnlockMessage(message string) {
+	unlockMessage = message
+}
+
+func DrawUnlocks(screenWidth, screenHeight float32) {
+	title := "UNLOCKS"
+	rl.DrawText(title, int32(screenWidth/2)-rl.MeasureText(title, 40)/2, 40, 40, rl.White)
+
+	shells := fmt.Sprintf("Shells: %d", profile.Get().Shells)
+	rl.DrawText(shells, int32(screenWidth/2)-rl.MeasureText(shells, 20)/2, 90, 20, rl.Gold)
+
+	list := profile.Unlocks()
+	startY := int32(130)
+	for i, option := range menuOptions {
+		y := startY + int32(i)*30
+
+		color := rl.White
+		if i == selectedOption {
+			color = rl.Yellow
+			rl.DrawText(">", 60, y, 20, rl.Yellow)
+		}
+		rl.DrawText(option, 85, y, 20, color)
+
+		if i >= len(list) {
+			continue
+		}
+		u := &list[i]
+		if u.Kind == profile.ColorUnlock {
+			rl.DrawRectangle(260, y+3, 14, 14, u.Tint())
+			rl.DrawRectangleLines(260, y+3, 14, 14, rl.White)
+		}
+
+		var status string
+		cost, buyable := u.NextCost()
+		switch {
+		case u.Equipped():
+			status = "Equipped"
+		case u.Kind == profile.ColorUnlock && u.Owned():
+			status = "Owned"
+		case u.Kind == profile.UpgradeUnlock && buyable:
+			status = fmt.Sprintf("Lv %d/%d  %d", profile.Level(u.ID), u.MaxLevel(), cost)
+		case u.Kind == profile.UpgradeUnlock:
+			status = fmt.Sprintf("Lv %d/%d  MAX", profile.Level(u.ID), u.MaxLevel())
+		case buyable:
+			status = fmt.Sprintf("%d", cost)
+		default:
+			status = "Owned"
+		}
+		statusColor := rl.LightGray
+		if buyable && cost > profile.Get().Shells {
+			statusColor = rl.Gray
+		}
+		rl.DrawText(status, int32(screenWidth)-60-rl.MeasureText(status, 20), y, 20, statusColor)
+	}
+
+	// Details of the selected entry
+	if selectedOption < len(list) {
+		rl.DrawText(list[selectedOption].Description, 85, int32(screenHeight-80), 18, rl.LightGray)
+	}
+	if unlockMessage != "" {
+		rl.DrawText(unlockMessage, 85, int32(screenHeight-60), 16, rl.Orange)
+	}
+
+	rl.DrawText("W/S: Navigate  •  Enter: Buy / Equip", 140, int32(screenHeight-30), 16, rl.Gray)
+}
@@ -0,0 +1,241 @@
+package upgrade
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"axelot/pkg/asset"
+	"axelot/pkg/rng"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Stat is the player value an upgrade changes
+type Stat string
+
+const (
+	Damage        Stat = "damage"        // multiplier on all attack damage
+	Cooldown      Stat = "cooldown"      // multiplier on the weapon cooldown
+	ComboWindow   Stat = "comboWindow"   // frames to continue a combo
+	ChargeRadius  Stat = "chargeRadius"  // multiplier on the charge burst reach
+	MaxHealth     Stat = "maxHealth"     // also heals by the same amount
+	RegenInterval Stat = "regenInterval" // frames between regenerated hearts
+	Speed         Stat = "speed"         // multiplier on movement speed
+)
+
+// Op is how stacks combine, "add" sums the amount, "mul" compounds it
+type Op string
+
+const (
+	Add      Op = "add"
+	Multiply Op = "mul"
+)
+
+type Rarity struct {
+	Weight int      `json:"weight"` // relative chance to be offered
+	Color  [3]uint8 `json:"color"`
+}
+
+// Def is one upgrade that can show up on the level up screen
+type Def struct {
+	ID          string  `json:"id"`
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	Rarity      string  `json:"rarity"`
+	Stat        Stat    `json:"stat"`
+	Op          Op      `json:"op"`
+	Amount      float32 `json:"amount"`
+	MaxStacks   int     `json:"maxStacks"`          // 0 = no limit
+	Requires    string  `json:"requires,omitempty"` // only offered once this upgrade was taken
+}
+
+type upgradeFile struct {
+	Rarities map[string]Rarity `json:"rarities"`
+	Upgrades []Def             `json:"upgrades"`
+}
+
+var (
+	rarities = map[string]Rarity{}
+	defs     []Def
+	stacks   = map[string]int{} // times each upgrade was taken this run
+)
+
+// Built-in pool, used when the data file is missing or broken
+var (
+	DefaultRarities = map[string]Rarity{
+		"common": {Weight: 60, Color: [3]uint8{220, 220, 220}},
+		"rare":   {Weight: 30, Color: [3]uint8{100, 180, 255}},
+		"epic":   {Weight: 10, Color: [3]uint8{200, 120, 255}},
+	}
+	DefaultUpgrades = []Def{
+		{ID: "sharp_gills", Name: "Sharp Gills", Description: "+15% damage", Rarity: "common", Stat: Damage, Op: Multiply, Amount: 1.15, MaxStacks: 5},
+		{ID: "quick_fins", Name: "Quick Fins", Description: "Attack 10% faster", Rarity: "common", Stat: Cooldown, Op: Multiply, Amount: 0.9, MaxStacks: 4},
+		{ID: "thick_skin", Name: "Thick Skin", Description: "+2 max health", Rarity: "common", Stat: MaxHealth, Op: Add, Amount: 2, MaxStacks: 5},
+	}
+)
+
+func InitUpgrades(file string) {
+	if err := LoadUpgrades(file); err != nil {
+		fmt.Println("upgrades:", err, "- using built-in upgrades")
+		rarities = DefaultRarities
+		defs = DefaultUpgrades
+	}
+	ResetUpgrades()
+}
+
+func LoadUpgrades(file string) error {
+	byteValue, err := asset.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	var data upgradeFile
+	if err := json.Unmarshal(byteValue, &data); err != nil {
+		return fmt.Errorf("%s: %w", file, err)
+	}
+	if len(data.Upgrades) == 0 {
+		return fmt.Errorf("%s: no upgrades defined", file)
+	}
+
+	ids := map[string]bool{}
+	for i := range data.Upgrades {
+		if err := data.Upgrades[i].validate(data.Rarities); err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+		ids[data.Upgrades[i].ID] = true
+	}
+	for _, def := range data.Upgrades {
+		if def.Requires != "" && !ids[def.Requires] {
+			return fmt.Errorf("%s: %s requires unknown upgrade %q", file, def.ID, def.Requires)
+		}
+	}
+
+	rarities = data.Rarities
+	defs = data.Upgrades
+	return nil
+}
+
+func (d *Def) validate(rarities map[string]Rarity) error {
+	if d.ID == "" || d.Name == "" {
+		return fmt.Errorf("upgrade without id or name")
+	}
+	if _, ok := rarities[d.Rarity]; !ok {
+		return fmt.Errorf("%s: unknown rarity %q", d.ID, d.Rarity)
+	}
+	op, err := CheckEffect(d.Stat, d.Op, d.Amount)
+	if err != nil {
+		return fmt.Errorf("%s: %w", d.ID, err)
+	}
+	d.Op = op
+	return nil
+}
+
+// CheckEffect validates a stat change, shared with the permanent upgrades of the profile.
+// An empty op means Add, the op to use is returned.
+func CheckEffect(stat Stat, op Op, amount float32) (Op, error) {
+	if !ValidStat(stat) {
+		return op, fmt.Errorf("unknown stat %q", stat)
+	}
+	switch op {
+	case "":
+		op = Add
+	case Add, Multiply:
+	default:
+		return op, fmt.Errorf("unknown op %q", op)
+	}
+	if op == Multiply && amount <= 0 {
+		return op, fmt.Errorf("multiplier must be positive")
+	}
+	return op, nil
+}
+
+func ValidStat(s Stat) bool {
+	switch s {
+	case Damage, Cooldown, ComboWindow, ChargeRadius, MaxHealth, RegenInterval, Speed:
+		return true
+	}
+	return false
+}
+
+// available is whether def can still be offered this run
+func available(def *Def) bool {
+	if def.MaxStacks > 0 && stacks[def.ID] >= def.MaxStacks {
+		return false
+	}
+	return def.Requires == "" || stacks[def.Requires] > 0
+}
+
+// Roll picks up to n different upgrades, weighted by rarity
+func Roll(n int) []*Def {
+	var pool []*Def
+	for i := range defs {
+		if available(&defs[i]) {
+			pool = append(pool, &defs[i])
+		}
+	}
+
+	var offers []*Def
+	for len(offers) < n && len(pool) > 0 {
+		total := 0
+		for _, def := range pool {
+			total += rarities[def.Rarity].Weight
+		}
+		if total <= 0 {
+			break
+		}
+
+		pick := rng.Intn(total)
+		for i, def := range pool {
+			pick -= rarities[def.Rarity].Weight
+			if pick < 0 {
+				offers = append(offers, def)
+				pool = append(pool[:i], pool[i+1:]...)
+				break
+			}
+		}
+	}
+	return offers
+}
+
+// Take records one more stack of def
+func Take(def *Def) {
+	stacks[def.ID]++
+}
+
+func Stacks(id string) int {
+	return stacks[id]
+}
+
+func ResetUpgrades() {
+	stacks = map[string]int{}
+}
+
+func (d *Def) Color() rl.Color {
+	c := rarities[d.Rarity].Color
+	return rl.NewColor(c[0], c[1], c[2], 255)
+}
+
+// Apply returns value changed by one stack of the upgrade
+func (d *Def) Apply(value float32) float32 {
+	if d.Op == Multiply {
+		return value * d.Amount
+	}
+	return value + d.Amount
+}
+
+// Snapshot copies the stacks taken this run, for save games
+func Snapshot() map[string]int {
+	saved := make(map[string]int, len(stacks))
+	for id, n := range stacks {
+		saved[id] = n
+	}
+	return saved
+}
+
+// Restore puts the stacks back, the stats they gave are restored by the player
+func Restore(saved map[string]int) {
+	ResetUpgrades()
+	for id, n := range saved {
+		stacks[id] = n
+	}
+}
@@ -0,0 +1,309 @@
+package waves
+
+import (
+	"axelot/pkg/asset"
+	"axelot/pkg/console"
+	"axelot/pkg/rng"
+	"axelot/pkg/slime"
+	"encoding/json"
+	"fmt"
+	"math"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+const (
+	screenWidth  = 600
+	screenHeight = 600
+)
+
+type EnemyGroup struct {
+	Type  string `json:"type"`
+	Count int    `json:"count"`
+}
+
+type Wave struct {
+	Enemies  []EnemyGroup `json:"enemies"`
+	Interval int          `json:"interval"`       // frames between spawns
+	Rest     int          `json:"rest"`           // frames of calm after the wave is cleared
+	Boss     string       `json:"boss,omitempty"` // spawned first, not scaled by difficulty
+}
+
+// Difficulty grows with survival time and kills and scales counts, intervals and the cap
+type Difficulty struct {
+	PerMinute float32 `json:"perMinute"`
+	PerKill   float32 `json:"perKill"`
+	Max       float32 `json:"max"`
+	CapGrowth float32 `json:"capGrowth"` // extra concurrent enemies per difficulty point
+}
+
+type WaveTable struct {
+	MaxConcurrent int        `json:"maxConcurrent"`
+	ClearTimeout  int        `json:"clearTimeout"` // frames after the last spawn before the wave counts as over anyway
+	Difficulty    Difficulty `json:"difficulty"`
+	Waves         []Wave     `json:"waves"`
+}
+
+type phase int
+
+const (
+	spawning phase = iota
+	clearing
+	resting
+)
+
+var (
+	table WaveTable
+
+	// Used when assets/waves.json can't be read
+	defaultTable = WaveTable{
+		MaxConcurrent: 10,
+		ClearTimeout:  1800,
+		Difficulty:    Difficulty{PerMinute: 0.15, PerKill: 0.01, Max: 3, CapGrowth: 4},
+		Waves: []Wave{
+			{Enemies: []EnemyGroup{{Type: "jellyfish", Count: 4}}, Interval: 120, Rest: 300},
+		},
+	}
+
+	waveNumber   int
+	currentPhase phase
+	spawnQueue   []string
+	spawnTimer   int
+	phaseTimer   int
+	difficulty   float32 = 1
+
+	bannerTimer    int
+	bannerDuration int = 150
+
+	// Frames between spawns for every wave when above 0, set from the console
+	spawnInterval int
+)
+
+func InitWaves(file string) {
+	if err := LoadWaveTable(file); err != nil {
+		fmt.Println("waves:", err, "- using built-in wave")
+		table = defaultTable
+	}
+	ResetWaves()
+
+	console.IntVar("spawnInterval", "frames between spawns, 0 uses the wave table", &spawnInterval)
+}
+
+func LoadWaveTable(file string) error {
+	byteValue, err := asset.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	var data WaveTable
+	if err := json.Unmarshal(byteValue, &data); err != nil {
+		return fmt.Errorf("%s: %w", file, err)
+	}
+
+	if len(data.Waves) == 0 {
+		return fmt.Errorf("%s: no waves defined", file)
+	}
+	for i, w := range data.Waves {
+		for _, group := range w.Enemies {
+			if _, ok := slime.GetEnemyType(group.Type); !ok {
+				return fmt.Errorf("%s: wave %d: unknown enemy type %q", file, i+1, group.Type)
+			}
+		}
+		if w.Boss != "" {
+			if def, ok := slime.GetEnemyType(w.Boss); !ok || def.Boss == nil {
+				return fmt.Errorf("%s: wave %d: %q is not a boss", file, i+1, w.Boss)
+			}
+		}
+		if w.Interval <= 0 {
+			data.Waves[i].Interval = 60
+		}
+	}
+	if data.MaxConcurrent <= 0 {
+		data.MaxConcurrent = defaultTable.MaxConcurrent
+	}
+	if data.ClearTimeout <= 0 {
+		data.ClearTimeout = defaultTable.ClearTimeout
+	}
+	if data.Difficulty.Max < 1 {
+		data.Difficulty.Max = 1
+	}
+
+	table = data
+	return nil
+}
+
+func ResetWaves() {
+	waveNumber = 0
+	difficulty = 1
+	spawnQueue = spawnQueue[:0]
+	startWave()
+}
+
+// UpdateWaves runs the spawn director, survivalTime is in frames
+func UpdateWaves(survivalTime, kills int) {
+	difficulty = 1 + float32(survivalTime)/3600*table.Difficulty.PerMinute + float32(kills)*table.Difficulty.PerKill
+	if difficulty > table.Difficulty.Max {
+		difficulty = table.Difficulty.Max
+	}
+
+	if bannerTimer > 0 {
+		bannerTimer--
+	}
+
+	phaseTimer++
+
+	switch currentPhase {
+	case spawning:
+		spawnTimer--
+		if spawnTimer <= 0 && slime.GetAliveCount() < GetConcurrentCap() {
+			if slime.SpawnEnemy(spawnQueue[0]) {
+				spawnQueue = spawnQueue[1:]
+			}
+			spawnTimer = scaledInterval()
+		}
+
+		if len(spawnQueue) == 0 {
+			currentPhase = clearing
+			phaseTimer = 0
+		}
+
+	case clearing:
+		// A boss fight doesn't time out
+		if slime.GetAliveCount() == 0 || (phaseTimer >= table.ClearTimeout && !slime.IsBossActive()) {
+			currentPhase = resting
+			phaseTimer = 0
+		}
+
+	case resting:
+		if phaseTimer >= currentWave().Rest {
+			startWave()
+		}
+	}
+}
+
+func startWave() {
+	waveNumber++
+	wave := currentWave()
+
+	spawnQueue = spawnQueue[:0]
+	for _, group := range wave.Enemies {
+		count := int(math.Round(float64(float32(group.Count) * difficulty)))
+		for i := 0; i < count; i++ {
+			spawnQueue = append(spawnQueue, group.Type)
+		}
+	}
+
+	// Mix the types so a wave doesn't arrive in blocks
+	rng.Shuffle(len(spawnQueue), func(i, j int) {
+		spawnQueue[i], spawnQueue[j] = spawnQueue[j], spawnQueue[i]
+	})
+	if wave.Boss != "" {
+		spawnQueue = append([]string{wave.Boss}, spawnQueue...)
+	}
+
+	currentPhase = spawning
+	if len(spawnQueue) == 0 {
+		currentPhase = clearing
+	}
+	phaseTimer = 0
+	spawnTimer = 0
+	bannerTimer = bannerDuration
+}
+
+// currentWave loops the last wave once the table runs out, difficulty keeps it growing
+func currentWave() *Wave {
+	index := waveNumber - 1
+	if index >= len(table.Waves) {
+		index = len(table.Waves) - 1
+	}
+	if index < 0 {
+		index = 0
+	}
+	return &table.Waves[index]
+}
+
+func scaledInterval() int {
+	if spawnInterval > 0 {
+		return spawnInterval
+	}
+	interval := int(float32(currentWave().Interval) / difficulty)
+	if interval < 10 {
+		interval = 10
+	}
+	return interval
+}
+
+func GetConcurrentCap() int {
+	return table.MaxConcurrent + int((difficulty-1)*table.Difficulty.CapGrowth)
+}
+
+func GetWaveNumber() int {
+	return waveNumber
+}
+
+func GetDifficulty() float32 {
+	return difficulty
+}
+
+func DrawWaveHUD() {
+	waveText := fmt.Sprintf("Wave %d", waveNumber)
+	rl.DrawText(waveText, screenWidth-rl.MeasureText(waveText, 20)-10, 10, 20, rl.White)
+
+	if currentPhase == resting {
+		restLeft := (currentWave().Rest - phaseTimer) / 60
+		nextText := fmt.Sprintf("Next wave in %d", restLeft+1)
+		rl.DrawText(nextText, screenWidth-rl.MeasureText(nextText, 14)-10, 34, 14, rl.LightGray)
+	}
+
+	// Banner fades in and out at the start of a wave
+	if bannerTimer > 0 {
+		t := float32(bannerTimer) / float32(bannerDuration)
+		alpha := float32(1)
+		if t > 0.8 {
+			alpha = (1 - t) / 0.2
+		} else if t < 0.3 {
+			alpha = t / 0.3
+		}
+
+		bannerText := fmt.Sprintf("WAVE %d", waveNumber)
+		if currentWave().Boss != "" {
+			bannerText = "BOSS WAVE"
+		}
+		width := rl.MeasureText(bannerText, 48)
+		rl.DrawText(bannerText, int32(screenWidth/2)-width/2+2, 182, 48, rl.Fade(rl.Black, alpha*0.5))
+		rl.DrawText(bannerText, int32(screenWidth/2)-width/2, 180, 48, rl.Fade(rl.White, alpha))
+	}
+}
+
+// Snapshot is the spawn director part of a save game
+type Snapshot struct {
+	Wave        int      `json:"wave"`
+	Phase       int      `json:"phase"`
+	Queue       []string `json:"queue"`
+	SpawnTimer  int      `json:"spawnTimer"`
+	PhaseTimer  int      `json:"phaseTimer"`
+	Difficulty  float32  `json:"difficulty"`
+	BannerTimer int      `json:"bannerTimer"`
+}
+
+func TakeSnapshot() Snapshot {
+	return Snapshot{
+		Wave:        waveNumber,
+		Phase:       int(currentPhase),
+		Queue:       append([]string(nil), spawnQueue...),
+		SpawnTimer:  spawnTimer,
+		PhaseTimer:  phaseTimer,
+		Difficulty:  difficulty,
+		BannerTimer: bannerTimer,
+	}
+}
+
+func RestoreSnapshot(snap Snapshot) {
+	waveNumber = snap.Wave
+	currentPhase = phase(snap.Phase)
+	spawnQueue = append(spawnQueue[:0], snap.Queue...)
+	spawnTimer = snap.SpawnTimer
+	phaseTimer = snap.PhaseTimer
+	difficulty = snap.Difficulty
+	bannerTimer = snap.BannerTimer
+}
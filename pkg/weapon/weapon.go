@@ -0,0 +1,151 @@
+package weapon
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"axelot/pkg/asset"
+	"axelot/pkg/projectile"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+type Kind string
+
+const (
+	Melee      Kind = "melee"
+	Projectile Kind = "projectile" // fires a shot instead of needing to close in
+)
+
+// ChargeCurve maps how long the charge attack was held to a damage multiplier
+type ChargeCurve struct {
+	MinTime         int     `json:"minTime"`         // frames before the charge does more than a normal hit
+	MaxTime         int     `json:"maxTime"`         // frames until full charge
+	MaxMultiplier   float32 `json:"maxMultiplier"`   // damage multiplier at full charge
+	Exponent        float32 `json:"exponent"`        // 1 = linear, >1 rewards holding longer
+	RangeMultiplier float32 `json:"rangeMultiplier"` // charge burst reach compared to Range
+}
+
+type Weapon struct {
+	Name           string      `json:"name"`
+	Kind           Kind        `json:"kind"`
+	Damage         float32     `json:"damage"`
+	Range          float32     `json:"range"`
+	Arc            float32     `json:"arc"` // degrees in front of the player
+	Cooldown       int         `json:"cooldown"`
+	ComboBonus     float32     `json:"comboBonus"` // extra damage per combo step
+	Charge         ChargeCurve `json:"charge"`
+	DashMultiplier float32     `json:"dashMultiplier"`
+	Color          [3]uint8    `json:"color"`
+
+	// What projectile weapons fire, the water bolt when left out
+	Shot *projectile.Def `json:"projectile,omitempty"`
+}
+
+type weaponFile struct {
+	Weapons []Weapon `json:"weapons"`
+}
+
+// Built-in set, used when the data file is missing or broken
+var DefaultWeapons = []Weapon{
+	{
+		Name:           "Gill Swipe",
+		Kind:           Melee,
+		Damage:         1.2,
+		Range:          40,
+		Arc:            120,
+		Cooldown:       30,
+		ComboBonus:     0.3,
+		Charge:         ChargeCurve{MinTime: 15, MaxTime: 60, MaxMultiplier: 2.5, Exponent: 1, RangeMultiplier: 1.5},
+		DashMultiplier: 1.5,
+		Color:          [3]uint8{255, 170, 200},
+	},
+}
+
+func LoadWeapons(file string) ([]Weapon, error) {
+	byteValue, err := asset.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var data weaponFile
+	if err := json.Unmarshal(byteValue, &data); err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+
+	if len(data.Weapons) == 0 {
+		return nil, fmt.Errorf("%s: no weapons defined", file)
+	}
+
+	for i := range data.Weapons {
+		if err := data.Weapons[i].validate(); err != nil {
+			return nil, fmt.Errorf("%s: weapon %d: %w", file, i, err)
+		}
+	}
+
+	return data.Weapons, nil
+}
+
+func (w *Weapon) validate() error {
+	if w.Name == "" {
+		return fmt.Errorf("missing name")
+	}
+	if w.Kind != Melee && w.Kind != Projectile {
+		return fmt.Errorf("%s: unknown kind %q", w.Name, w.Kind)
+	}
+	if w.Damage <= 0 || w.Range <= 0 || w.Cooldown < 0 {
+		return fmt.Errorf("%s: damage and range must be positive", w.Name)
+	}
+	if w.Charge.MaxTime <= 0 {
+		return fmt.Errorf("%s: charge maxTime must be positive", w.Name)
+	}
+	if w.Charge.Exponent <= 0 {
+		w.Charge.Exponent = 1
+	}
+	if w.Charge.RangeMultiplier <= 0 {
+		w.Charge.RangeMultiplier = 1
+	}
+	if w.Kind == Projectile {
+		if w.Shot == nil {
+			shot := projectile.WaterBolt
+			w.Shot = &shot
+		}
+		if err := w.Shot.Validate(); err != nil {
+			return fmt.Errorf("%s: %w", w.Name, err)
+		}
+	}
+	return nil
+}
+
+// ChargeDamage returns the damage of a charge attack held for chargeTime frames
+func (w *Weapon) ChargeDamage(chargeTime int) float32 {
+	if chargeTime < w.Charge.MinTime {
+		// Too quick, just do normal damage
+		return w.Damage
+	}
+
+	return w.Damage * w.ChargeMultiplier(chargeTime)
+}
+
+func (w *Weapon) ChargeMultiplier(chargeTime int) float32 {
+	progress := float64(chargeTime) / float64(w.Charge.MaxTime)
+	if progress > 1 {
+		progress = 1
+	}
+
+	curve := float32(math.Pow(progress, float64(w.Charge.Exponent)))
+	return 1 + (w.Charge.MaxMultiplier-1)*curve
+}
+
+// ComboDamage is the basic attack damage at the given combo step
+func (w *Weapon) ComboDamage(combo int) float32 {
+	if combo <= 1 {
+		return w.Damage
+	}
+	return w.Damage + w.ComboBonus*float32(combo)
+}
+
+func (w *Weapon) Tint() rl.Color {
+	return rl.NewColor(w.Color[0], w.Color[1], w.Color[2], 255)
+}
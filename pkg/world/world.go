@@ -1,9 +1,9 @@
 package world
 
 import (
+	"axelot/pkg/asset"
 	"encoding/json"
-	"io/ioutil"
-	"os"
+	"fmt"
 	"strconv"
 
 	rl "github.com/gen2brain/raylib-go/raylib"
@@ -39,26 +39,45 @@ type Tile struct {
 }
 
 func LoadMap(mapFile string) {
-	file, err := os.Open(mapFile)
+	byteValue, err := asset.ReadFile(mapFile)
 
 	if err != nil {
 		panic(err)
 	}
 
-	defer file.Close()
+	json.Unmarshal(byteValue, &WorldMap)
+	assignLayers()
+}
 
-	byteValue, _ := ioutil.ReadAll(file)
+// ReloadMap swaps in another map file, the current one stays when it can't be read
+func ReloadMap(mapFile string) error {
+	byteValue, err := asset.ReadFile(mapFile)
+	if err != nil {
+		return err
+	}
 
-	json.Unmarshal(byteValue, &WorldMap)
+	var loaded JsonMap
+	if err := json.Unmarshal(byteValue, &loaded); err != nil {
+		return fmt.Errorf("%s: %w", mapFile, err)
+	}
+	if loaded.TileSize <= 0 || len(loaded.Layers) == 0 {
+		return fmt.Errorf("%s: no tiles", mapFile)
+	}
+
+	WorldMap = loaded
+	GroundTiles, Structures, WaterTiles = nil, nil, nil
+	assignLayers()
+	return nil
 }
 
 func InitWorld() {
-	SpritesheetMap = rl.LoadTexture("assets/spritesheet.png")
+	SpritesheetMap = asset.Acquire("world.tiles")
 	tileDest = rl.NewRectangle(0, 0, 16, 16)
 	tileSrc = rl.NewRectangle(0, 0, 16, 16)
 }
 
-func DrawWorld() {
+// assignLayers fills the tile slices as soon as the map is loaded, so collision and pathfinding work before the first draw
+func assignLayers() {
 	for i := 0; i < len(WorldMap.Layers); i++ {
 		if WorldMap.Layers[i].Name == "Land" {
 			GroundTiles = WorldMap.Layers[i].Tiles
@@ -73,7 +92,9 @@ func DrawWorld() {
 		}
 
 	}
+}
 
+func DrawWorld() {
 	rl.DrawTexturePro(tex, tileSrc, tileDest, rl.NewVector2(0, 0), 0, rl.White)
 
 	RenderLayer(WaterTiles)
@@ -100,5 +121,13 @@ func RenderLayer(Layer []Tile) {
 }
 
 func UnloadWorldTexture() {
-	rl.UnloadTexture(SpritesheetMap)
+	asset.Release("world.tiles")
+}
+
+// DrawCollisionDebug outlines the ground tiles the player and enemies collide with
+func DrawCollisionDebug() {
+	size := float32(WorldMap.TileSize)
+	for _, tile := range GroundTiles {
+		rl.DrawRectangleLinesEx(rl.NewRectangle(float32(tile.X)*size, float32(tile.Y)*size, size, size), 1, rl.Magenta)
+	}
 }